@@ -0,0 +1,109 @@
+package automationlog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type Entry struct {
+	Artist    string
+	Title     string
+	Album     string
+	Category  string
+	StartTime time.Time
+}
+
+var ErrInvalidMapping = errors.New("invalid automation log mapping")
+
+// Parse reads an as-run log export, dropping rows whose category is excluded
+// by the mapping (station IDs, PSAs, ads) and rows without a title. Exports
+// that only record the time of day are anchored to logDate, if given.
+func Parse(content []byte, mapping Mapping, logDate time.Time) ([]Entry, error) {
+	delimiter, size := utf8.DecodeRuneInString(mapping.Delimiter)
+	if size == 0 || size != len(mapping.Delimiter) || mapping.TitleColumn == "" {
+		return nil, ErrInvalidMapping
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]int)
+	if mapping.HasHeader && len(records) > 0 {
+		for i, name := range records[0] {
+			headers[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		records = records[1:]
+	}
+	columnIndex := func(column string) (int, error) {
+		if column == "" {
+			return -1, nil
+		}
+		if mapping.HasHeader {
+			if index, ok := headers[strings.ToLower(column)]; ok {
+				return index, nil
+			}
+			return -1, fmt.Errorf("%w: column '%s' not found", ErrInvalidMapping, column)
+		}
+		number, err := strconv.Atoi(column)
+		if err != nil || number < 1 {
+			return -1, fmt.Errorf("%w: column '%s' is not a column number", ErrInvalidMapping, column)
+		}
+		return number - 1, nil
+	}
+	indexes := make([]int, 5)
+	for i, column := range []string{
+		mapping.ArtistColumn,
+		mapping.TitleColumn,
+		mapping.AlbumColumn,
+		mapping.StartTimeColumn,
+		mapping.CategoryColumn,
+	} {
+		if indexes[i], err = columnIndex(column); err != nil {
+			return nil, err
+		}
+	}
+	field := func(record []string, index int) string {
+		if index < 0 || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+	entries := make([]Entry, 0, len(records))
+	for _, record := range records {
+		entry := Entry{
+			Artist:   field(record, indexes[0]),
+			Title:    field(record, indexes[1]),
+			Album:    field(record, indexes[2]),
+			Category: field(record, indexes[4]),
+		}
+		if entry.Title == "" || mapping.isExcluded(entry.Category) {
+			continue
+		}
+		if startTime := field(record, indexes[3]); startTime != "" && mapping.StartTimeLayout != "" {
+			if parsed, err := time.ParseInLocation(mapping.StartTimeLayout, startTime, time.Local); err == nil {
+				switch {
+				case parsed.Year() > 0:
+					entry.StartTime = parsed
+				case !logDate.IsZero():
+					entry.StartTime = time.Date(
+						logDate.Year(), logDate.Month(), logDate.Day(),
+						parsed.Hour(), parsed.Minute(), parsed.Second(), 0,
+						time.Local,
+					)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
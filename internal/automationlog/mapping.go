@@ -0,0 +1,106 @@
+package automationlog
+
+import "strings"
+
+// Mapping describes an as-run log export. Column values are header names when
+// the export has a header row, otherwise 1-based column numbers.
+type Mapping struct {
+	Delimiter          string   `json:"delimiter"`
+	HasHeader          bool     `json:"hasHeader"`
+	ArtistColumn       string   `json:"artistColumn"`
+	TitleColumn        string   `json:"titleColumn"`
+	AlbumColumn        string   `json:"albumColumn"`
+	StartTimeColumn    string   `json:"startTimeColumn"`
+	StartTimeLayout    string   `json:"startTimeLayout"`
+	CategoryColumn     string   `json:"categoryColumn"`
+	ExcludedCategories []string `json:"excludedCategories"`
+}
+
+// MappingOverride changes the fields of a mapping that it sets. HasHeader is
+// a pointer so that an override can turn off a preset's header row.
+type MappingOverride struct {
+	Delimiter          string   `json:"delimiter"`
+	HasHeader          *bool    `json:"hasHeader"`
+	ArtistColumn       string   `json:"artistColumn"`
+	TitleColumn        string   `json:"titleColumn"`
+	AlbumColumn        string   `json:"albumColumn"`
+	StartTimeColumn    string   `json:"startTimeColumn"`
+	StartTimeLayout    string   `json:"startTimeLayout"`
+	CategoryColumn     string   `json:"categoryColumn"`
+	ExcludedCategories []string `json:"excludedCategories"`
+}
+
+var Presets = map[string]Mapping{
+	// RadioDJ history export, where song_type is numeric: 1 jingle,
+	// 2 sweeper, 3 voice over, 4 commercial, 10 news, 14 teaser
+	"radiodj": {
+		Delimiter:          ",",
+		HasHeader:          true,
+		ArtistColumn:       "artist",
+		TitleColumn:        "title",
+		AlbumColumn:        "album",
+		StartTimeColumn:    "date_played",
+		StartTimeLayout:    "2006-01-02 15:04:05",
+		CategoryColumn:     "song_type",
+		ExcludedCategories: []string{"1", "2", "3", "4", "10", "14"},
+	},
+	"mairlist": {
+		Delimiter:          "\t",
+		HasHeader:          true,
+		ArtistColumn:       "Artist",
+		TitleColumn:        "Title",
+		AlbumColumn:        "Album",
+		StartTimeColumn:    "Start Time",
+		StartTimeLayout:    "2006-01-02 15:04:05",
+		CategoryColumn:     "Type",
+		ExcludedCategories: []string{"Jingle", "Advertising", "Promo", "Voice", "News", "Command", "Silence"},
+	},
+	"rivendell": {
+		Delimiter:          ",",
+		HasHeader:          true,
+		ArtistColumn:       "Artist",
+		TitleColumn:        "Title",
+		AlbumColumn:        "Album",
+		StartTimeColumn:    "Air Time",
+		StartTimeLayout:    "15:04:05",
+		CategoryColumn:     "Group",
+		ExcludedCategories: []string{"LEGAL", "ID", "PSA", "COMMERCIAL", "TRAFFIC", "PROMO", "SWEEPER", "LINER"},
+	},
+}
+
+// Merge overlays the fields override sets onto mapping.
+func (mapping Mapping) Merge(override MappingOverride) Mapping {
+	merged := mapping
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&merged.Delimiter, override.Delimiter},
+		{&merged.ArtistColumn, override.ArtistColumn},
+		{&merged.TitleColumn, override.TitleColumn},
+		{&merged.AlbumColumn, override.AlbumColumn},
+		{&merged.StartTimeColumn, override.StartTimeColumn},
+		{&merged.StartTimeLayout, override.StartTimeLayout},
+		{&merged.CategoryColumn, override.CategoryColumn},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	if override.HasHeader != nil {
+		merged.HasHeader = *override.HasHeader
+	}
+	if override.ExcludedCategories != nil {
+		merged.ExcludedCategories = override.ExcludedCategories
+	}
+	return merged
+}
+
+func (mapping Mapping) isExcluded(category string) bool {
+	for _, excluded := range mapping.ExcludedCategories {
+		if strings.EqualFold(strings.TrimSpace(category), excluded) {
+			return true
+		}
+	}
+	return false
+}
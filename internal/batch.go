@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/insert"
+)
+
+const (
+	batchCollectionId           = "batch"
+	maximumBatchItems           = 50
+	maximumConcurrentBatchItems = 4
+	maximumBatchBodyBytes       = 64 * 1024
+)
+
+type batchRequest struct {
+	Items []string `json:"items"`
+}
+
+// createBatchFunction returns a handler function that fetches a list of links,
+// or "{provider}/{type}/{identifier}" paths as used by the provider routes,
+// concurrently. A failed item is reported in its place rather than failing
+// the batch.
+func createBatchFunction(trackInfoHandlers []handler.TrackInfoHandler, enrichment trackEnrichment, rowInserter *insert.Inserter) func(*http.Request) (handler.BatchInfo, error) {
+	return func(request *http.Request) (handler.BatchInfo, error) {
+		var body batchRequest
+		if err := json.NewDecoder(io.LimitReader(request.Body, maximumBatchBodyBytes)).Decode(&body); err != nil {
+			return handler.BatchInfo{}, handler.NewInvalidRequestError(err.Error())
+		}
+		if len(body.Items) == 0 {
+			return handler.BatchInfo{}, handler.NewInvalidRequestError("'items' is required")
+		}
+		if len(body.Items) > maximumBatchItems {
+			return handler.BatchInfo{}, handler.NewInvalidRequestError(fmt.Sprintf("at most %d items are allowed", maximumBatchItems))
+		}
+		trackSelection, err := handler.NewTrackSelection(request.URL.Query())
+		if err != nil {
+			return handler.BatchInfo{}, err
+		}
+		trackSchedule, err := handler.NewTrackSchedule(request.URL.Query())
+		if err != nil {
+			return handler.BatchInfo{}, err
+		}
+		enrichmentOptions, err := enrichment.newOptions(request.URL.Query())
+		if err != nil {
+			return handler.BatchInfo{}, err
+		}
+		insertion, err := rowInserter.NewInsertion(request.URL.Query())
+		if err != nil {
+			return handler.BatchInfo{}, err
+		}
+		// selection, entries, the schedule and enrichment apply across every
+		// item rather than within each, so CanCon lookups share one budget
+		itemQuery := insert.WithoutParams(request.URL.Query())
+		for _, param := range slices.Concat(handler.SelectionParams, handler.ScheduleParams, enrichmentParams) {
+			itemQuery.Del(param)
+		}
+		itemRequest := request.Clone(request.Context())
+		itemRequest.URL.RawQuery = itemQuery.Encode()
+		itemInfos := make([]handler.BatchItemInfo, len(body.Items))
+		itemTracks := make([][]handler.TrackInfo, len(body.Items))
+		slots := make(chan struct{}, maximumConcurrentBatchItems)
+		var waitGroup sync.WaitGroup
+		for i, item := range body.Items {
+			waitGroup.Add(1)
+			go func(i int, item string) {
+				defer waitGroup.Done()
+				slots <- struct{}{}
+				defer func() { <-slots }()
+				itemInfos[i], itemTracks[i] = fetchBatchItem(trackInfoHandlers, strings.TrimSpace(item), itemRequest, rowInserter)
+			}(i, item)
+		}
+		waitGroup.Wait()
+		tracks := make([]handler.TrackInfo, 0)
+		for _, trackInfos := range itemTracks {
+			tracks = append(tracks, trackInfos...)
+		}
+		// positions count across every item, in order
+		tracks = enrichment.apply(enrichmentOptions, insertion.Apply(trackSelection.Apply(tracks)))
+		return handler.BatchInfo{
+			TrackCollectionInfo: trackSchedule.Apply(handler.NewTrackCollectionInfo(tracks, batchCollectionId)),
+			Items:               itemInfos,
+		}, nil
+	}
+}
+
+func fetchBatchItem(trackInfoHandlers []handler.TrackInfoHandler, item string, request *http.Request, rowInserter *insert.Inserter) (handler.BatchItemInfo, []handler.TrackInfo) {
+	itemInfo := handler.BatchItemInfo{
+		Item: item,
+	}
+	resolved, err := resolveBatchItem(trackInfoHandlers, item)
+	if err == nil {
+		itemInfo.Provider = resolved.trackInfoHandler.Identifier()
+		itemInfo.Type = constants.RequestTypeNames[resolved.requestType]
+		var collectionInfo handler.TrackCollectionInfo
+		collectionInfo, err = fetchResolvedLink(resolved, request, trackEnrichment{}, rowInserter)
+		if err == nil {
+			itemInfo.Status = http.StatusOK
+			itemInfo.TrackCount = len(collectionInfo.Tracks)
+			return itemInfo, collectionInfo.Tracks
+		}
+	}
+	itemInfo.Status, itemInfo.Error = statusCodeFromError(err)
+	if itemInfo.Error == "" {
+		itemInfo.Error = http.StatusText(itemInfo.Status)
+	}
+	return itemInfo, nil
+}
+
+func resolveBatchItem(trackInfoHandlers []handler.TrackInfoHandler, item string) (resolvedLink, error) {
+	if item == "" {
+		return resolvedLink{}, handler.NewInvalidRequestError("empty item")
+	}
+	parts := strings.SplitN(strings.TrimPrefix(item, "/"), "/", 3)
+	if len(parts) == 3 {
+		for _, trackInfoHandler := range trackInfoHandlers {
+			if trackInfoHandler.Identifier() != parts[0] {
+				continue
+			}
+			for requestType, requestTypeName := range constants.RequestTypeNames {
+				if _, ok := constants.RequestTypeIdentifierParams[requestType]; ok && requestTypeName == parts[1] {
+					return resolvedLink{
+						trackInfoHandler: trackInfoHandler,
+						requestType:      requestType,
+						identifier:       parts[2],
+					}, nil
+				}
+			}
+		}
+	}
+	return resolveLink(trackInfoHandlers, item)
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRU is a fixed-size cache that is safe for concurrent use. Once full, the
+// least recently used entry is evicted to make room for a new one.
+type LRU[K comparable, V any] struct {
+	capacity int
+	entries  map[K]*list.Element
+	order    *list.List
+	mutex    sync.Mutex
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: max(capacity, 1),
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (lru *LRU[K, V]) Get(key K) (V, bool) {
+	lru.mutex.Lock()
+	defer lru.mutex.Unlock()
+	element, ok := lru.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	lru.order.MoveToFront(element)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+func (lru *LRU[K, V]) Add(key K, value V) {
+	lru.mutex.Lock()
+	defer lru.mutex.Unlock()
+	if element, ok := lru.entries[key]; ok {
+		element.Value.(*lruEntry[K, V]).value = value
+		lru.order.MoveToFront(element)
+		return
+	}
+	lru.entries[key] = lru.order.PushFront(&lruEntry[K, V]{key, value})
+	if lru.order.Len() > lru.capacity {
+		oldest := lru.order.Back()
+		lru.order.Remove(oldest)
+		delete(lru.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
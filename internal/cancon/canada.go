@@ -0,0 +1,51 @@
+package cancon
+
+import "strings"
+
+const canadaCountryCode = "CA"
+
+// Areas below country level do not always carry ISO codes, so Canada's
+// provinces and territories are also recognised by name.
+var canadianAreaNames = map[string]bool{
+	"canada":                    true,
+	"alberta":                   true,
+	"british columbia":          true,
+	"colombie-britannique":      true,
+	"manitoba":                  true,
+	"new brunswick":             true,
+	"nouveau-brunswick":         true,
+	"newfoundland and labrador": true,
+	"terre-neuve-et-labrador":   true,
+	"nova scotia":               true,
+	"nouvelle-écosse":           true,
+	"ontario":                   true,
+	"prince edward island":      true,
+	"île-du-prince-édouard":     true,
+	"quebec":                    true,
+	"québec":                    true,
+	"saskatchewan":              true,
+	"northwest territories":     true,
+	"territoires du nord-ouest": true,
+	"nunavut":                   true,
+	"yukon":                     true,
+}
+
+// isCanadian reports whether an origin is in Canada and whether that is
+// known at all. An origin with only a city is not known, as cities share
+// names across countries.
+func isCanadian(origin ArtistOrigin) (canadian bool, known bool) {
+	for _, code := range origin.AreaCodes {
+		if code == canadaCountryCode || strings.HasPrefix(code, canadaCountryCode+"-") {
+			return true, true
+		}
+	}
+	for _, area := range origin.Areas {
+		if canadianAreaNames[strings.ToLower(area)] {
+			return true, true
+		}
+	}
+	if origin.Country != "" {
+		return origin.Country == canadaCountryCode, true
+	}
+	return false, false
+}
@@ -0,0 +1,229 @@
+package cancon
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/cache"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+// MAPL criteria. A recording is Canadian content when it meets at least two:
+// the music or lyrics are performed principally by a Canadian (Artist), the
+// music is composed by a Canadian (Music), the lyrics are written by a
+// Canadian (Lyrics), or it was recorded or performed and broadcast live
+// wholly in Canada (Performance).
+const (
+	CriterionMusic       = "M"
+	CriterionArtist      = "A"
+	CriterionPerformance = "P"
+	CriterionLyrics      = "L"
+)
+
+var Criteria = []string{CriterionMusic, CriterionArtist, CriterionPerformance, CriterionLyrics}
+
+const requiredCriteria = 2
+
+const (
+	// lookups for one request stop after this long, leaving the remaining
+	// artists unknown until a later request looks them up
+	lookupBudget    = 20 * time.Second
+	originCacheSize = 10000
+)
+
+var errLookupBudgetSpent = errors.New("origin lookup time for the request is spent")
+
+var creditSeparatorRegex = regexp.MustCompile(`(?i)\s*(?:,|&|\bfeat\.?|\bft\.|\bfeaturing\b)\s*`)
+
+// ListedArtist is an artist the station knows to be Canadian. Criteria lists
+// the MAPL criteria the artist's recordings meet; an artist listed without
+// criteria is vouched for by the station and their tracks are Canadian
+// content.
+type ListedArtist struct {
+	Name     string   `json:"name"`
+	Criteria []string `json:"criteria"`
+}
+
+// ArtistOrigin is where a source places an artist. Country is an ISO 3166-1
+// code and area codes may be ISO 3166-1 or 3166-2; any may be empty.
+type ArtistOrigin struct {
+	Name      string
+	Country   string
+	Areas     []string
+	AreaCodes []string
+}
+
+// Assessor scores tracks against the MAPL criteria. Only the Artist
+// criterion can be inferred from an artist's origin; the others come from
+// the station's list, which is always consulted first. Origins are cached
+// across requests, as lookups are rate limited and playlists repeat artists.
+type Assessor struct {
+	listedArtists map[string]ListedArtist
+	originLookup  func(string) (ArtistOrigin, bool, error)
+	originCache   *cache.LRU[string, originResult]
+}
+
+type originResult struct {
+	origin ArtistOrigin
+	found  bool
+}
+
+type originLookupResult struct {
+	originResult
+	err error
+}
+
+func NewAssessor(
+	listedArtists []ListedArtist,
+	originLookup func(string) (ArtistOrigin, bool, error),
+) *Assessor {
+	assessor := &Assessor{
+		listedArtists: make(map[string]ListedArtist, len(listedArtists)),
+		originLookup:  originLookup,
+		originCache:   cache.NewLRU[string, originResult](originCacheSize),
+	}
+	for _, listedArtist := range listedArtists {
+		assessor.listedArtists[normaliseName(listedArtist.Name)] = listedArtist
+	}
+	return assessor
+}
+
+// Apply sets the CanCon assessment of each track. Non-music rows are left
+// as they are. Origins not already cached are looked up until the request's
+// lookup budget is spent.
+func (assessor *Assessor) Apply(tracks []handler.TrackInfo) []handler.TrackInfo {
+	deadline := time.Now().Add(lookupBudget)
+	assessed := make([]handler.TrackInfo, len(tracks))
+	for i, track := range tracks {
+		if track.RowType == "" {
+			track.CanCon, track.CanConReasons = assessor.Assess(track.Artist, deadline)
+		}
+		assessed[i] = track
+	}
+	return assessed
+}
+
+// Assess reports whether an artist's tracks are Canadian content, with the
+// reasons for the answer. The full artist credit is tried first, so that
+// names such as "Simon & Garfunkel" are not split, then the principal
+// (first credited) artist. Origins that are not cached are not looked up
+// after the deadline.
+func (assessor *Assessor) Assess(artist string, deadline time.Time) (string, []string) {
+	names := creditedNames(artist)
+	if len(names) == 0 {
+		return handler.AssessmentUnknown, []string{"No artist to assess"}
+	}
+	for _, name := range names {
+		if listedArtist, ok := assessor.listedArtists[normaliseName(name)]; ok {
+			return assessListedArtist(listedArtist)
+		}
+	}
+	reasons := make([]string, 0)
+	for _, name := range names {
+		origin, found, err := assessor.lookupOrigin(name, deadline)
+		if errors.Is(err, errLookupBudgetSpent) {
+			reasons = append(reasons, fmt.Sprintf("Origin of %s was not looked up in the time allowed for the request", name))
+			break
+		}
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("Origin of %s could not be looked up", name))
+			continue
+		}
+		if !found {
+			continue
+		}
+		switch canadian, known := isCanadian(origin); {
+		case canadian:
+			return handler.AssessmentUnknown, []string{
+				fmt.Sprintf("Meets %s: %s is from %s", CriterionArtist, origin.Name, originDescription(origin)),
+				"No other MAPL criterion is known; the track is Canadian content if it was also written or recorded in Canada",
+			}
+		case known:
+			// M, P and L can still qualify the track, so only the station's
+			// list gives a definite answer
+			return handler.AssessmentUnknown, []string{
+				fmt.Sprintf("%s is from %s, not Canada", origin.Name, originDescription(origin)),
+				"Canadian songwriting and recording could still qualify the track",
+			}
+		}
+		reasons = append(reasons, fmt.Sprintf("Origin of %s is not recorded", origin.Name))
+		break
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, fmt.Sprintf("%s is not on the station's list and was not found", names[0]))
+	}
+	return handler.AssessmentUnknown, reasons
+}
+
+func assessListedArtist(listedArtist ListedArtist) (string, []string) {
+	if len(listedArtist.Criteria) == 0 {
+		return handler.AssessmentTrue, []string{fmt.Sprintf("%s is listed by the station as Canadian content", listedArtist.Name)}
+	}
+	reasons := []string{fmt.Sprintf("%s is listed by the station as meeting %s", listedArtist.Name, strings.Join(listedArtist.Criteria, ", "))}
+	if len(listedArtist.Criteria) >= requiredCriteria {
+		return handler.AssessmentTrue, reasons
+	}
+	return handler.AssessmentUnknown, append(reasons, fmt.Sprintf("MAPL requires %d criteria", requiredCriteria))
+}
+
+// Failed lookups are not cached so that they are retried. A lookup still
+// running at the deadline is left to finish and cache its result for later
+// requests.
+func (assessor *Assessor) lookupOrigin(name string, deadline time.Time) (ArtistOrigin, bool, error) {
+	key := normaliseName(name)
+	if result, ok := assessor.originCache.Get(key); ok {
+		return result.origin, result.found, nil
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return ArtistOrigin{}, false, errLookupBudgetSpent
+	}
+	lookups := make(chan originLookupResult, 1)
+	go func() {
+		origin, found, err := assessor.originLookup(name)
+		if err == nil {
+			assessor.originCache.Add(key, originResult{origin: origin, found: found})
+		}
+		lookups <- originLookupResult{originResult: originResult{origin: origin, found: found}, err: err}
+	}()
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case lookup := <-lookups:
+		return lookup.origin, lookup.found, lookup.err
+	case <-timer.C:
+		return ArtistOrigin{}, false, errLookupBudgetSpent
+	}
+}
+
+func creditedNames(artist string) []string {
+	artist = strings.TrimSpace(artist)
+	if artist == "" {
+		return nil
+	}
+	names := []string{artist}
+	if principal := strings.TrimSpace(creditSeparatorRegex.Split(artist, 2)[0]); principal != "" && principal != artist {
+		names = append(names, principal)
+	}
+	return names
+}
+
+func originDescription(origin ArtistOrigin) string {
+	if len(origin.Areas) > 0 {
+		return strings.Join(origin.Areas, ", ")
+	}
+	return origin.Country
+}
+
+func normaliseName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// ValidCriterion reports whether criterion is one of M, A, P or L.
+func ValidCriterion(criterion string) bool {
+	return slices.Contains(Criteria, criterion)
+}
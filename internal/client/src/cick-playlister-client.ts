@@ -1,6 +1,6 @@
 import { components } from "./generated/types";
-import { FillRowResult, HandlerData, Provider } from "./types";
-import { Spotify } from "./providers/spotify";
+import { FillRowResult } from "./types";
+import { Common, UnsupportedUrlError } from "./providers/common";
 import { apiUrlBase } from "./constants";
 
 type TrackInfo = components["schemas"]["TrackInfo"];
@@ -13,29 +13,15 @@ class CickPlaylisterClient {
   private readonly urlInputId: string = "cick-playlister-input";
   private readonly formSubmitId: string = "cick-playlister-submit";
   private readonly feedbackElementId: string = "cick-playlister-feedback";
+  private readonly providersElementId: string = "cick-playlister-providers";
   private readonly trackHashAttribute: string = "data-row-track-hash";
   private readonly trackHashEmptyValue: string = "empty";
+  private readonly insertedRowHashPrefix: string = "inserted-";
   private readonly trackRowCounterAttribute: string = "data-row-counter";
   private readonly trackSingleValue: string = "Single";
-  private readonly providers: {[index: string]: Provider} = {
-    [Spotify.identifier]: new Spotify(),
-  };
   private readonly boundEscapeKeyHandler: (event: KeyboardEvent) => void = this.escapeKeyHandler.bind(this);
 
   public show(): void {
-    const providerIcons = Object.entries(this.providers).map(([identifier, provider]) => {
-      return `
-      <span class="modal-supported-provider">
-        <img
-          src="${apiUrlBase}/client/assets/${provider.icon}"
-          alt="${identifier}"
-          title="${identifier}"
-          width="20px"
-          height="20px"
-          />
-      </span>
-      `
-    });
     const anchor = this.anchor;
     anchor.innerHTML = `
       <div id="cick-playlister-modal">
@@ -49,7 +35,7 @@ class CickPlaylisterClient {
               <span class="modal-supported-providers-title">
                 Supported Services
               </span>
-              ${providerIcons}
+              <span id="${this.providersElementId}"></span>
             </div>
             <form onsubmit="window.cickPlaylisterClient.processInput(); return false;">
               <div class="modal-input-container">
@@ -85,9 +71,43 @@ class CickPlaylisterClient {
     `;
     anchor.style.display = "block";
     this.urlInput.focus();
+    this.showProviders();
     window.addEventListener("keydown", this.boundEscapeKeyHandler);
   }
 
+  // only providers that links can be pasted for, and that are usable, are shown
+  private showProviders(): void {
+    Common.providers(apiUrlBase)
+      .then(providers => {
+        const providerIcons = providers
+          .filter(provider => provider.urlPatterns.length > 0 && provider.credentials !== "invalid")
+          .map(provider => {
+            if (!provider.icon) {
+              return `<span class="modal-supported-provider">${provider.identifier}</span>`;
+            }
+            return `
+            <span class="modal-supported-provider">
+              <img
+                src="${apiUrlBase}/client/assets/${provider.icon}"
+                alt="${provider.identifier}"
+                title="${provider.identifier}"
+                width="20px"
+                height="20px"
+                />
+            </span>
+            `;
+          });
+        const providersElement = document.getElementById(this.providersElementId);
+        if (providersElement) {
+          providersElement.innerHTML = providerIcons.join("");
+        }
+      })
+      .catch(err => {
+        console.log(err);
+      })
+    ;
+  }
+
   public hide(): void {
     const anchor = this.anchor;
     anchor.innerHTML = "";
@@ -102,14 +122,25 @@ class CickPlaylisterClient {
       this.reportFeedback("URL is empty");
       return;
     }
-    var handlerData = this.getHandlerData(url);
-    if (!handlerData) {
-      this.reportFeedback("URL type is not currently supported");
-      return;
-    }
     this.disableUrlInput();
-    this.updateHandleTypeDisplay(handlerData.provider, handlerData.type);
-    handlerData.handle(apiUrlBase)
+    this.reportFeedback("Processing...");
+    // several links may be pasted at once, separated by spaces
+    const urls = url.split(/\s+/).filter(part => part.length > 0);
+    const failedUrls: string[] = [];
+    const tracksPromise = urls.length > 1
+      ? Common.batch(apiUrlBase, urls)
+        .then(batchInfo => {
+          batchInfo.items
+            .filter(item => item.status !== 200)
+            .forEach(item => failedUrls.push(item.item));
+          return batchInfo.tracks;
+        })
+      : Common.resolve(apiUrlBase, url.trim())
+        .then(resolvedInfo => {
+          this.updateHandleTypeDisplay(resolvedInfo.provider, resolvedInfo.type);
+          return resolvedInfo.tracks;
+        });
+    tracksPromise
       .then(tracks => {
         this.classifyTableRows();
         const counts = {
@@ -124,7 +155,13 @@ class CickPlaylisterClient {
               track: track.track,
               album: track.isSingle ? this.trackSingleValue : track.album,
               isNew: track.isNew,
+              newReason: track.newReason,
               isSingle: track.isSingle,
+              label: track.label,
+              canCon: track.canCon,
+              isInstrumental: track.isInstrumental,
+              rowType: track.rowType,
+              estimatedStartTime: track.estimatedStartTime,
             };
             switch(this.fillRow(track)) {
               case FillRowResult.Success:
@@ -138,12 +175,15 @@ class CickPlaylisterClient {
                 break;
             }
           });
-          if (counts.success === tracks.length) {
+          if (counts.success === tracks.length && failedUrls.length === 0) {
             this.reportFeedback(`${this.trackCountString(counts.success)} filled successfully`);
           } else {
             const feedbackParts = [
               `${this.trackCountString(counts.success)} filled`,
             ];
+            if (failedUrls.length > 0) {
+              feedbackParts.push(`problem with ${failedUrls.join(", ")}`);
+            }
             if (counts.noFreeRow > 0) {
               feedbackParts.push(`${this.trackCountString(counts.noFreeRow)} skipped as all rows are filled`);
             }
@@ -153,13 +193,17 @@ class CickPlaylisterClient {
             this.reportFeedback(feedbackParts.join(", "));
           }
         } else {
-          this.reportFeedback("No tracks found");
+          this.reportFeedback(failedUrls.length > 0 ? `No tracks found, problem with ${failedUrls.join(", ")}` : "No tracks found");
         }
         this.enableUrlInput();
       })
       .catch(err => {
         console.log(err);
-        this.reportFeedback(`Problem with this URL, please check it is correct`);
+        if (err instanceof UnsupportedUrlError) {
+          this.reportFeedback("URL is not supported, please check it is correct");
+        } else {
+          this.reportFeedback(`Problem with this URL, please check it is correct`);
+        }
         this.enableUrlInput();
       })
     ;
@@ -169,15 +213,6 @@ class CickPlaylisterClient {
     this.clearFeedback();
   }
 
-  private getHandlerData(url: string): HandlerData | undefined {
-    for (var key in this.providers) {
-      var handlerData = this.providers[key].getHandlerData(url);
-      if (!!handlerData) {
-        return handlerData;
-      }
-    };
-  }
-
   private get anchor(): HTMLElement {
     return document.getElementById(this.anchorId)!;
   }
@@ -222,6 +257,7 @@ class CickPlaylisterClient {
       let trackInput: HTMLInputElement | null = null;
       let albumInput: HTMLInputElement | null = null;
       let isNewInput: HTMLInputElement | null = null;
+      let stationIdInput: HTMLInputElement | null = null;
       let rowCounter: number = -1;
       const artistInputIdRegex = /^edit-tracks-(\d+)-artist$/
       Array.from(rowEl.getElementsByTagName("input")).forEach(inputEl => {
@@ -232,6 +268,7 @@ class CickPlaylisterClient {
           trackInput = this.getTrackInput(rowCounter);
           albumInput = this.getAlbumInput(rowCounter);
           isNewInput = this.getIsNewInput(rowCounter);
+          stationIdInput = this.getStationIdInput(rowCounter);
         }
       });
       if (artistInput && trackInput && albumInput && isNewInput) {
@@ -247,6 +284,13 @@ class CickPlaylisterClient {
             album: (albumInput as HTMLInputElement).value,
             isNew: (isNewInput as HTMLInputElement).checked,
           });
+        } else if (
+          (artistInput as HTMLInputElement).value ||
+          (trackInput as HTMLInputElement).value ||
+          this.hasStationIdTime(stationIdInput)
+        ) {
+          // station IDs and PSAs have no album, but their rows are not free
+          hashValue = `${this.insertedRowHashPrefix}${rowCounter}`;
         }
         rowEl.setAttribute(this.trackHashAttribute, hashValue);
         rowEl.setAttribute(this.trackRowCounterAttribute, rowCounter.toString());
@@ -255,6 +299,9 @@ class CickPlaylisterClient {
   }
 
   private fillRow(track: TrackInfo): FillRowResult {
+    if (track.rowType) {
+      return this.fillInsertedRow(track);
+    }
     const trackHash = this.trackUniqueIdentifier(track);
     const existingRowEls = document.querySelectorAll(`[${this.trackHashAttribute}="${trackHash}"]`);
     if (existingRowEls.length != 0) {
@@ -272,10 +319,56 @@ class CickPlaylisterClient {
     this.getTrackInput(rowCounter).value = track.track;
     this.getAlbumInput(rowCounter).value = track.album;
     this.getIsNewInput(rowCounter).checked = track.isNew;
+    // the host can hover over NEW to see why it was or was not ticked
+    if (track.newReason) {
+      this.getIsNewInput(rowCounter).title = track.newReason;
+    }
+    // the form's "link" column records talk breaks, so a track's link is not filled
+    const labelInput = this.getLabelInput(rowCounter);
+    if (labelInput && track.label) {
+      labelInput.value = track.label;
+    }
+    // only a confident assessment is filled; the host decides the rest
+    const canConInput = this.getCanConInput(rowCounter);
+    if (canConInput && track.canCon === "true") {
+      canConInput.checked = true;
+    }
+    const instrumentalInput = this.getInstrumentalInput(rowCounter);
+    if (instrumentalInput && track.isInstrumental === "true") {
+      instrumentalInput.checked = true;
+    }
     nextRow.setAttribute(this.trackHashAttribute, trackHash);
     return FillRowResult.Success;
   }
 
+  // station IDs and PSAs repeat, so they are never skipped as duplicates
+  private fillInsertedRow(track: TrackInfo): FillRowResult {
+    const emptyRowEls = document.querySelectorAll(`[${this.trackHashAttribute}="${this.trackHashEmptyValue}"]`);
+    if (emptyRowEls.length === 0) {
+      console.log(`no rows available for ${track.rowType} '${track.track}'`);
+      return FillRowResult.NoFreeRow;
+    }
+    const nextRow = emptyRowEls[0];
+    const rowCounter = parseInt(nextRow.getAttribute(this.trackRowCounterAttribute)!, 10);
+    if (track.rowType === "stationId") {
+      // the host enters the time when the show's start was not given
+      const stationIdInput = this.getStationIdInput(rowCounter);
+      if (stationIdInput && track.estimatedStartTime) {
+        const estimatedStartTime = new Date(track.estimatedStartTime);
+        stationIdInput.value = `${estimatedStartTime.getHours()}:${estimatedStartTime.getMinutes().toString().padStart(2, "0")}`;
+      }
+    } else {
+      this.getArtistInput(rowCounter).value = track.artist;
+      this.getTrackInput(rowCounter).value = track.track;
+    }
+    nextRow.setAttribute(this.trackHashAttribute, `${this.insertedRowHashPrefix}${rowCounter}`);
+    return FillRowResult.Success;
+  }
+
+  private hasStationIdTime(stationIdInput: HTMLInputElement | null): boolean {
+    return !!stationIdInput && stationIdInput.value !== "" && stationIdInput.value !== "0";
+  }
+
   private getArtistInput(rowCounter: number): HTMLInputElement {
     return document.getElementById("edit-tracks-" + rowCounter + "-artist") as HTMLInputElement;
   }
@@ -288,6 +381,22 @@ class CickPlaylisterClient {
     return document.getElementById("edit-tracks-" + rowCounter + "-album") as HTMLInputElement;
   }
   
+  private getLabelInput(rowCounter: number): HTMLInputElement | null {
+    return document.getElementById("edit-tracks-" + rowCounter + "-label") as HTMLInputElement | null;
+  }
+
+  private getCanConInput(rowCounter: number): HTMLInputElement | null {
+    return document.getElementById("edit-tracks-" + rowCounter + "-cancontrack") as HTMLInputElement | null;
+  }
+
+  private getInstrumentalInput(rowCounter: number): HTMLInputElement | null {
+    return document.getElementById("edit-tracks-" + rowCounter + "-instrack") as HTMLInputElement | null;
+  }
+
+  private getStationIdInput(rowCounter: number): HTMLInputElement | null {
+    return document.getElementById("edit-tracks-" + rowCounter + "-stationid") as HTMLInputElement | null;
+  }
+
   private getIsNewInput(rowCounter: number): HTMLInputElement {
     return document.getElementById("edit-tracks-" + rowCounter + "-newtrack") as HTMLInputElement;
   }
@@ -1,58 +1,54 @@
 import { components } from "../generated/types";
 
-type TrackInfo = components["schemas"]["TrackInfo"];
-type TrackCollectionInfo = components["schemas"]["TrackCollectionInfo"];
+type ResolvedInfo = components["schemas"]["ResolvedInfo"];
+type ProviderInfo = components["schemas"]["ProviderInfo"];
+type BatchInfo = components["schemas"]["BatchInfo"];
 
 export class Common {
 
-  public static getPlaylistFetcher(provider: string, playlistId: string): (apiUrl: string) => Promise<TrackInfo[]> {
-    return async function(apiUrlBase: string) {
-      return fetch(`${apiUrlBase}/${provider}/playlist/${playlistId}`)
-        .then(async response => {
-          if (response.ok) {
-            return response.json()
-              .then((data: TrackCollectionInfo) => {
-                return data.tracks;
-              });
-          } else {
-            throw new Error("Unexpected API response for Playlist ID");
-          }
-        })
-      ;
-    };
+  public static async providers(apiUrlBase: string): Promise<ProviderInfo[]> {
+    return fetch(`${apiUrlBase}/providers`)
+      .then(async response => {
+        if (response.ok) {
+          return response.json();
+        } else {
+          throw new Error("Unexpected API response for providers");
+        }
+      })
+    ;
   }
 
-  public static getAlbumFetcher(provider: string, albumId: string): (apiUrl: string) => Promise<TrackInfo[]> {
-    return async function(apiUrlBase: string) {
-      return fetch(`${apiUrlBase}/${provider}/album/${albumId}`)
-        .then(async response => {
-          if (response.ok) {
-            return response.json()
-              .then((data: TrackCollectionInfo) => {
-                return data.tracks;
-              });
-          } else {
-            throw new Error("Unexpected API response for Album ID");
-          }
-        })
-      ;
-    };
+  public static async resolve(apiUrlBase: string, url: string): Promise<ResolvedInfo> {
+    return fetch(`${apiUrlBase}/resolve?url=${encodeURIComponent(url)}&canCon=true`)
+      .then(async response => {
+        if (response.ok) {
+          return response.json();
+        } else if (response.status === 400) {
+          throw new UnsupportedUrlError(await response.text());
+        } else {
+          throw new Error("Unexpected API response for URL");
+        }
+      })
+    ;
   }
 
-  public static getTrackFetcher(provider: string, trackId: string): (apiUrl: string) => Promise<TrackInfo[]>{
-    return async function(apiUrlBase: string) {
-      return fetch(`${apiUrlBase}/${provider}/track/${trackId}`)
-        .then(async response => {
-          if (response.ok) {
-            return response.json()
-              .then((data: TrackInfo) => {
-                return [data];
-              });
-          } else {
-            throw new Error("Unexpected API response for Track ID");
-          }
-        })
-      ;
-    };
+  public static async batch(apiUrlBase: string, urls: string[]): Promise<BatchInfo> {
+    return fetch(`${apiUrlBase}/batch?canCon=true`, {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify({ items: urls }),
+    })
+      .then(async response => {
+        if (response.ok) {
+          return response.json();
+        } else {
+          throw new Error("Unexpected API response for URLs");
+        }
+      })
+    ;
   }
 }
+
+export class UnsupportedUrlError extends Error {}
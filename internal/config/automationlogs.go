@@ -0,0 +1,23 @@
+package config
+
+import "github.com/captaincoordinates/cick-playlister/internal/automationlog"
+
+type AutomationLogConfig struct {
+	Mappings map[string]automationlog.Mapping `json:"mappings"`
+}
+
+// NewAutomationLogConfig reads station-specific automation log mappings,
+// which add to or replace the built-in presets by name.
+func NewAutomationLogConfig() *AutomationLogConfig {
+	configuration := AutomationLogConfig{}
+	readConfiguration("automation-logs.json", &configuration)
+	mappings := make(map[string]automationlog.Mapping, len(automationlog.Presets)+len(configuration.Mappings))
+	for name, mapping := range automationlog.Presets {
+		mappings[name] = mapping
+	}
+	for name, mapping := range configuration.Mappings {
+		mappings[name] = mapping
+	}
+	configuration.Mappings = mappings
+	return &configuration
+}
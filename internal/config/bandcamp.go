@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+type BandcampConfig struct {
+	CustomDomains []string `json:"customDomains"`
+}
+
+// NewBandcampConfig reads the custom domains of Bandcamp artists that do not
+// use an artist.bandcamp.com address. Without it only bandcamp.com pages can
+// be read.
+func NewBandcampConfig() *BandcampConfig {
+	configuration := BandcampConfig{}
+	configurationPath, _ := readConfiguration("bandcamp-domains.json", &configuration)
+	for i, domain := range configuration.CustomDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" || strings.ContainsAny(domain, ":/") {
+			panic(fmt.Sprintf("custom domain '%s' in '%s' must be a host name without a scheme, port or path", configuration.CustomDomains[i], configurationPath))
+		}
+		configuration.CustomDomains[i] = domain
+	}
+	return &configuration
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/cancon"
+)
+
+type CanConConfig struct {
+	Artists []cancon.ListedArtist `json:"artists"`
+}
+
+// NewCanConConfig reads the station's list of known Canadian artists. The
+// list is optional; without it CanCon is assessed from artist origins alone.
+func NewCanConConfig() *CanConConfig {
+	configuration := CanConConfig{}
+	configurationPath, _ := readConfiguration("canadian-artists.json", &configuration)
+	for i, artist := range configuration.Artists {
+		criteria := make([]string, 0, len(artist.Criteria))
+		for _, criterion := range artist.Criteria {
+			criterion = strings.ToUpper(strings.TrimSpace(criterion))
+			if !cancon.ValidCriterion(criterion) {
+				panic(fmt.Sprintf("invalid MAPL criterion '%s' for '%s' in '%s'", criterion, artist.Name, configurationPath))
+			}
+			if !slices.Contains(criteria, criterion) {
+				criteria = append(criteria, criterion)
+			}
+		}
+		configuration.Artists[i].Criteria = criteria
+	}
+	return &configuration
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// readConfiguration decodes a JSON file that sits alongside the binary into
+// configuration. It reports false if the file does not exist and panics on
+// any other problem, as a broken configuration file must be fixed before the
+// server is usable.
+func readConfiguration(fileName string, configuration any) (string, bool) {
+	binary, err := os.Executable()
+	if err != nil {
+		panic(err)
+	}
+	binaryDirectory := filepath.Dir(binary)
+	configurationPath := filepath.Join(binaryDirectory, fileName)
+	if _, fileExistsErr := os.Stat(configurationPath); fileExistsErr == nil {
+		configurationFile, fileOpenErr := os.Open(configurationPath)
+		if fileOpenErr != nil {
+			panic(fileOpenErr)
+		}
+		defer configurationFile.Close()
+		decoder := json.NewDecoder(configurationFile)
+		fileOpenErr = decoder.Decode(configuration)
+		if fileOpenErr != nil {
+			panic(fileOpenErr)
+		}
+		return configurationPath, true
+	} else if errors.Is(fileExistsErr, fs.ErrNotExist) {
+		return configurationPath, false
+	} else {
+		panic(fileExistsErr)
+	}
+}
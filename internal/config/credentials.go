@@ -1,39 +1,39 @@
 package config
 
-import (
-	"encoding/json"
-	"os"
-	"path/filepath"
-)
+import "fmt"
 
 type CredentialsConfig struct {
 	Spotify struct {
 		ClientID     string `json:"client_id"`
 		ClientSecret string `json:"client_secret"`
 	} `json:"spotify"`
+	AppleMusic struct {
+		TeamID     string `json:"team_id"`
+		KeyID      string `json:"key_id"`
+		PrivateKey string `json:"private_key"`
+		Storefront string `json:"storefront"`
+	} `json:"apple_music"`
+	YouTube struct {
+		APIKey string `json:"api_key"`
+	} `json:"youtube"`
+	SoundCloud struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	} `json:"soundcloud"`
+	Tidal struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+		CountryCode  string `json:"country_code"`
+	} `json:"tidal"`
+	Discogs struct {
+		Token string `json:"token"`
+	} `json:"discogs"`
 }
 
 func NewCredentialsConfig() *CredentialsConfig {
-	binary, err := os.Executable()
-	if err != nil {
-		panic(err)
-	}
-	binaryDirectory := filepath.Dir(binary)
-	configurationPath := filepath.Join(binaryDirectory, "credentials.json")
-	if _, fileExistsErr := os.Stat(configurationPath); fileExistsErr == nil {
-		configurationFile, fileOpenErr := os.Open(configurationPath)
-		if fileOpenErr != nil {
-			panic(fileOpenErr)
-		}
-		defer configurationFile.Close()
-		decoder := json.NewDecoder(configurationFile)
-		configuration := CredentialsConfig{}
-		fileOpenErr = decoder.Decode(&configuration)
-		if fileOpenErr != nil {
-			panic(fileOpenErr)
-		}
-		return &configuration
-	} else {
-		panic(fileExistsErr)
+	configuration := CredentialsConfig{}
+	if configurationPath, found := readConfiguration("credentials.json", &configuration); !found {
+		fmt.Printf("no credentials found at '%s', only providers without credentials will work\n", configurationPath)
 	}
+	return &configuration
 }
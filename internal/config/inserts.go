@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/captaincoordinates/cick-playlister/internal/insert"
+)
+
+type InsertConfig struct {
+	Entries   map[string]insert.Entry  `json:"entries"`
+	Templates map[string][]insert.Rule `json:"templates"`
+}
+
+// NewInsertConfig reads the station's station ID and PSA entries and the
+// show templates that place them. Without it only the default station ID can
+// be inserted.
+func NewInsertConfig() *InsertConfig {
+	configuration := InsertConfig{}
+	configurationPath, _ := readConfiguration("inserts.json", &configuration)
+	for name, entry := range configuration.Entries {
+		if !insert.ValidRowType(entry.Type) {
+			panic(fmt.Sprintf("invalid type '%s' for entry '%s' in '%s'", entry.Type, name, configurationPath))
+		}
+	}
+	for name, rules := range configuration.Templates {
+		for _, rule := range rules {
+			if _, ok := configuration.Entries[rule.Entry]; !ok && rule.Entry != insert.DefaultEntryName {
+				panic(fmt.Sprintf("unknown entry '%s' in template '%s' in '%s'", rule.Entry, name, configurationPath))
+			}
+			if rule.IsEmpty() || rule.EveryTracks < 0 || rule.EveryMinutes < 0 || slices.ContainsFunc(rule.Positions, func(position int) bool { return position < 1 }) {
+				panic(fmt.Sprintf("entry '%s' in template '%s' in '%s' needs a valid placement", rule.Entry, name, configurationPath))
+			}
+		}
+	}
+	return &configuration
+}
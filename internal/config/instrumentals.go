@@ -0,0 +1,15 @@
+package config
+
+import "github.com/captaincoordinates/cick-playlister/internal/instrumental"
+
+type InstrumentalConfig struct {
+	Overrides []instrumental.Override `json:"overrides"`
+}
+
+// NewInstrumentalConfig reads the station's overrides of whether tracks are
+// instrumental.
+func NewInstrumentalConfig() *InstrumentalConfig {
+	configuration := InstrumentalConfig{}
+	readConfiguration("instrumentals.json", &configuration)
+	return &configuration
+}
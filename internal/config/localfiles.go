@@ -0,0 +1,23 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+type LocalFilesConfig struct {
+	Roots []string `json:"roots"`
+}
+
+// NewLocalFilesConfig reads the directories whose contents may be read as
+// local files playlists. Without it no directory can be read.
+func NewLocalFilesConfig() *LocalFilesConfig {
+	configuration := LocalFilesConfig{}
+	configurationPath, _ := readConfiguration("local-files.json", &configuration)
+	for _, root := range configuration.Roots {
+		if !filepath.IsAbs(root) {
+			panic(fmt.Sprintf("root '%s' in '%s' must be an absolute path", root, configurationPath))
+		}
+	}
+	return &configuration
+}
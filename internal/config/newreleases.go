@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+)
+
+type NewReleaseConfig struct {
+	MonthPrecision string                             `json:"monthPrecision"`
+	YearPrecision  string                             `json:"yearPrecision"`
+	Library        []newrelease.LibraryEntry          `json:"library"`
+	Shows          map[string]newrelease.ShowOverride `json:"shows"`
+}
+
+// NewNewReleaseConfig reads the station's new music list, how release dates
+// known only to the month or year are treated, and any show's own rules. The
+// window itself is set on the command line and may be changed per show.
+func NewNewReleaseConfig() *NewReleaseConfig {
+	configuration := NewReleaseConfig{}
+	configurationPath, _ := readConfiguration("new-releases.json", &configuration)
+	validatePrecisionPolicy(configuration.MonthPrecision, "monthPrecision", configurationPath)
+	validatePrecisionPolicy(configuration.YearPrecision, "yearPrecision", configurationPath)
+	for name, show := range configuration.Shows {
+		validatePrecisionPolicy(show.MonthPrecision, fmt.Sprintf("monthPrecision for show '%s'", name), configurationPath)
+		validatePrecisionPolicy(show.YearPrecision, fmt.Sprintf("yearPrecision for show '%s'", name), configurationPath)
+	}
+	return &configuration
+}
+
+func validatePrecisionPolicy(policy string, description string, configurationPath string) {
+	if policy != "" && !newrelease.ValidPrecisionPolicy(policy) {
+		panic(fmt.Sprintf("invalid %s '%s' in '%s'", description, policy, configurationPath))
+	}
+}
@@ -5,3 +5,5 @@ import "github.com/sirupsen/logrus"
 const DefaultPort uint = 8123
 const DefaultLogLevel logrus.Level = logrus.InfoLevel
 const DefaultNewReleaseDays uint = 180
+const DefaultMinimumPlayedSeconds float64 = 30
+const UserAgent = "cick-playlister/0.0.1 ( https://github.com/captaincoordinates/cick-playlister )"
@@ -6,14 +6,30 @@ const (
 	PlaylistRequestType RequestType = iota
 	AlbumRequestType
 	TrackRequestType
+	EpisodeRequestType
+	ShowRequestType
+	UploadRequestType
 )
 
 var RequestTypeNames = map[RequestType]string{
 	PlaylistRequestType: "playlist",
 	AlbumRequestType:    "album",
 	TrackRequestType:    "track",
+	EpisodeRequestType:  "episode",
+	ShowRequestType:     "show",
+	UploadRequestType:   "upload",
 }
 
 const PlaylistIdentifierParam = "playlistIdentifier"
 const AlbumIdentifierParam = "albumIdentifier"
 const TrackIdentifierParam = "trackIdentifier"
+const EpisodeIdentifierParam = "episodeIdentifier"
+const ShowIdentifierParam = "showIdentifier"
+
+var RequestTypeIdentifierParams = map[RequestType]string{
+	PlaylistRequestType: PlaylistIdentifierParam,
+	AlbumRequestType:    AlbumIdentifierParam,
+	TrackRequestType:    TrackIdentifierParam,
+	EpisodeRequestType:  EpisodeIdentifierParam,
+	ShowRequestType:     ShowIdentifierParam,
+}
@@ -0,0 +1,59 @@
+package djhistory
+
+import (
+	"bytes"
+	"errors"
+	"path"
+	"strings"
+	"time"
+)
+
+type Entry struct {
+	Artist    string
+	Title     string
+	Album     string
+	StartTime time.Time
+	// PlayedSeconds is negative when the format does not record how long a
+	// track was actually playing
+	PlayedSeconds float64
+}
+
+type Options struct {
+	// Playlist names the Rekordbox XML playlist node to read; the latest
+	// history playlist is used when empty
+	Playlist string
+}
+
+var ErrUnsupportedFormat = errors.New("unsupported DJ history format")
+
+const unknownPlayedSeconds = -1
+
+// Parse reads Serato history CSV, Rekordbox history M3U8 or XML, and Traktor
+// NML history files, in play order.
+func Parse(filename string, content []byte, options Options) ([]Entry, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return parseSeratoCsv(content)
+	case ".m3u", ".m3u8":
+		return parseRekordboxM3u(filename, content)
+	case ".xml":
+		return parseRekordboxXml(content, options.Playlist)
+	case ".nml":
+		return parseTraktorNml(content)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// FilterPlayed drops entries that were only loaded or previewed, where the
+// format records play time. Entries with unknown play time are kept.
+func FilterPlayed(entries []Entry, minimumPlayedSeconds float64) []Entry {
+	played := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.PlayedSeconds >= 0 && entry.PlayedSeconds < minimumPlayedSeconds {
+			continue
+		}
+		played = append(played, entry)
+	}
+	return played
+}
@@ -0,0 +1,54 @@
+package djhistory
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture %s: %v", name, err)
+	}
+	return content
+}
+
+func localTime(day int, hour int, minute int, second int) time.Time {
+	return time.Date(2024, time.March, day, hour, minute, second, 0, time.Local)
+}
+
+func TestParseUnsupportedFormat(t *testing.T) {
+	if _, err := Parse("history.txt", []byte("Artist - Title"), Options{}); err != ErrUnsupportedFormat {
+		t.Errorf("Parse: got error %v, want %v", err, ErrUnsupportedFormat)
+	}
+}
+
+func TestFilterPlayed(t *testing.T) {
+	played := Entry{Title: "played", PlayedSeconds: 45}
+	threshold := Entry{Title: "threshold", PlayedSeconds: 30}
+	previewed := Entry{Title: "previewed", PlayedSeconds: 5}
+	unknown := Entry{Title: "unknown", PlayedSeconds: unknownPlayedSeconds}
+	tests := []struct {
+		name    string
+		entries []Entry
+		minimum float64
+		want    []Entry
+	}{
+		{"drops previewed", []Entry{played, previewed, threshold}, 30, []Entry{played, threshold}},
+		{"keeps unknown play time", []Entry{unknown, previewed}, 30, []Entry{unknown}},
+		{"no minimum", []Entry{previewed, unknown}, 0, []Entry{previewed, unknown}},
+		{"empty", []Entry{}, 30, []Entry{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := FilterPlayed(test.entries, test.minimum)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("FilterPlayed:\n got %+v\nwant %+v", got, test.want)
+			}
+		})
+	}
+}
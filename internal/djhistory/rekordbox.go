@@ -0,0 +1,101 @@
+package djhistory
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/playlistfile"
+)
+
+const rekordboxHistoryPrefix = "HISTORY"
+const rekordboxPlaylistNodeType = "1"
+
+type rekordboxNode struct {
+	Name   string          `xml:"Name,attr"`
+	Type   string          `xml:"Type,attr"`
+	Nodes  []rekordboxNode `xml:"NODE"`
+	Tracks []struct {
+		Key string `xml:"Key,attr"`
+	} `xml:"TRACK"`
+}
+
+type rekordboxXml struct {
+	Collection []struct {
+		TrackID string `xml:"TrackID,attr"`
+		Name    string `xml:"Name,attr"`
+		Artist  string `xml:"Artist,attr"`
+		Album   string `xml:"Album,attr"`
+	} `xml:"COLLECTION>TRACK"`
+	Playlists rekordboxNode `xml:"PLAYLISTS>NODE"`
+}
+
+var errRekordboxPlaylistNotFound = errors.New("rekordbox playlist not found")
+
+// Rekordbox history playlists list what was loaded to a deck, without play
+// times, so entries from Rekordbox have unknown play time.
+func parseRekordboxM3u(filename string, content []byte) ([]Entry, error) {
+	playlistEntries, err := playlistfile.Parse(filename, content)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(playlistEntries))
+	for _, playlistEntry := range playlistEntries {
+		entries = append(entries, Entry{
+			Artist:        playlistEntry.Artist,
+			Title:         playlistEntry.Title,
+			Album:         playlistEntry.Album,
+			PlayedSeconds: unknownPlayedSeconds,
+		})
+	}
+	return entries, nil
+}
+
+func parseRekordboxXml(content []byte, playlistName string) ([]Entry, error) {
+	var data rekordboxXml
+	if err := xml.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+	var playlist *rekordboxNode
+	walkRekordboxPlaylists(&data.Playlists, func(node *rekordboxNode) {
+		switch {
+		case playlistName != "":
+			if node.Name == playlistName {
+				playlist = node
+			}
+		case strings.HasPrefix(strings.ToUpper(node.Name), rekordboxHistoryPrefix):
+			// history playlists are named by date, so the greatest name is the latest
+			if playlist == nil || node.Name > playlist.Name {
+				playlist = node
+			}
+		}
+	})
+	if playlist == nil {
+		return nil, errRekordboxPlaylistNotFound
+	}
+	tracksById := make(map[string]Entry, len(data.Collection))
+	for _, track := range data.Collection {
+		tracksById[track.TrackID] = Entry{
+			Artist:        track.Artist,
+			Title:         track.Name,
+			Album:         track.Album,
+			PlayedSeconds: unknownPlayedSeconds,
+		}
+	}
+	entries := make([]Entry, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		if entry, ok := tracksById[track.Key]; ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func walkRekordboxPlaylists(node *rekordboxNode, visit func(*rekordboxNode)) {
+	if node.Type == rekordboxPlaylistNodeType {
+		visit(node)
+	}
+	for i := range node.Nodes {
+		walkRekordboxPlaylists(&node.Nodes[i], visit)
+	}
+}
@@ -0,0 +1,107 @@
+package djhistory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+var seratoDateTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+	"01/02/2006 15:04:05",
+	"01/02/2006 3:04:05 PM",
+	"02/01/2006 15:04:05",
+}
+
+var seratoTimeLayouts = []string{
+	"15:04:05",
+	"3:04:05 PM",
+	"3:04:05 pm",
+}
+
+// Serato history exports start with a row describing the session, whose start
+// time carries the date that the time-of-day values in later rows lack.
+func parseSeratoCsv(content []byte) ([]Entry, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return []Entry{}, nil
+	}
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	field := func(record []string, names ...string) string {
+		for _, name := range names {
+			if index, ok := columns[name]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+		}
+		return ""
+	}
+	var sessionDate time.Time
+	entries := make([]Entry, 0, len(records)-1)
+	for _, record := range records[1:] {
+		title := field(record, "name", "title", "song")
+		artist := field(record, "artist")
+		startTime, hasDate := parseSeratoTime(field(record, "start time", "start"), sessionDate)
+		if hasDate && sessionDate.IsZero() {
+			sessionDate = startTime
+		}
+		if title == "" || artist == "" && field(record, "deck") == "" {
+			continue
+		}
+		playedSeconds := float64(unknownPlayedSeconds)
+		if playtime := handler.SecondsFromClockDuration(field(record, "playtime", "play time")); playtime > 0 {
+			playedSeconds = float64(playtime)
+		} else if endTime, _ := parseSeratoTime(field(record, "end time", "end"), sessionDate); !startTime.IsZero() && !endTime.IsZero() {
+			playedSeconds = endTime.Sub(startTime).Seconds()
+		}
+		entries = append(entries, Entry{
+			Artist:        artist,
+			Title:         title,
+			Album:         field(record, "album"),
+			StartTime:     startTime,
+			PlayedSeconds: playedSeconds,
+		})
+	}
+	return entries, nil
+}
+
+func parseSeratoTime(value string, sessionDate time.Time) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range seratoDateTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return parsed, true
+		}
+	}
+	if sessionDate.IsZero() {
+		return time.Time{}, false
+	}
+	for _, layout := range seratoTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			startTime := time.Date(
+				sessionDate.Year(), sessionDate.Month(), sessionDate.Day(),
+				parsed.Hour(), parsed.Minute(), parsed.Second(), 0,
+				time.Local,
+			)
+			// sessions that run past midnight
+			if startTime.Before(sessionDate) {
+				startTime = startTime.AddDate(0, 0, 1)
+			}
+			return startTime, false
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,51 @@
+package djhistory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSeratoCsv(t *testing.T) {
+	want := []Entry{
+		{Artist: "The Vancouver Sound", Title: "Northern Lights", Album: "Northern Lights", StartTime: localTime(8, 23, 52, 10), PlayedSeconds: 210},
+		// ends after midnight, so the end time is on the next day
+		{Artist: "Wheatfield", Title: "Grain Elevator", StartTime: localTime(8, 23, 58, 30), PlayedSeconds: 190},
+		{Artist: "The Vancouver Sound", Title: "Harbour Fog", Album: "Northern Lights", StartTime: localTime(9, 0, 1, 35), PlayedSeconds: 5},
+		{Artist: "Wheatfield", Title: "Long Road Home", Album: "Prairie Static", StartTime: localTime(9, 0, 2, 0), PlayedSeconds: unknownPlayedSeconds},
+	}
+	got, err := Parse("serato_session.csv", readFixture(t, "serato_session.csv"), Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseSeratoTime(t *testing.T) {
+	sessionDate := localTime(8, 23, 50, 0)
+	tests := []struct {
+		name        string
+		value       string
+		sessionDate time.Time
+		want        time.Time
+		hasDate     bool
+	}{
+		{"date and time", "2024-03-08 23:50:00", time.Time{}, sessionDate, true},
+		{"US date and 12 hour time", "03/08/2024 11:50:00 PM", time.Time{}, sessionDate, true},
+		{"time on the session date", "23:55:00", sessionDate, localTime(8, 23, 55, 0), false},
+		{"time after midnight", "00:10:00", sessionDate, localTime(9, 0, 10, 0), false},
+		{"12 hour time after midnight", "12:10:00 AM", sessionDate, localTime(9, 0, 10, 0), false},
+		{"time without a session date", "23:55:00", time.Time{}, time.Time{}, false},
+		{"empty", "", sessionDate, time.Time{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, hasDate := parseSeratoTime(test.value, test.sessionDate)
+			if !got.Equal(test.want) || hasDate != test.hasDate {
+				t.Errorf("parseSeratoTime(%q):\n got %v, %t\nwant %v, %t", test.value, got, hasDate, test.want, test.hasDate)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package djhistory
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type traktorLocation struct {
+	Dir    string `xml:"DIR,attr"`
+	File   string `xml:"FILE,attr"`
+	Volume string `xml:"VOLUME,attr"`
+}
+
+type traktorNml struct {
+	Collection []struct {
+		Title    string          `xml:"TITLE,attr"`
+		Artist   string          `xml:"ARTIST,attr"`
+		Location traktorLocation `xml:"LOCATION"`
+		Album    struct {
+			Title string `xml:"TITLE,attr"`
+		} `xml:"ALBUM"`
+	} `xml:"COLLECTION>ENTRY"`
+	Playlists []struct {
+		Entries []struct {
+			PrimaryKey struct {
+				Key string `xml:"KEY,attr"`
+			} `xml:"PRIMARYKEY"`
+			ExtendedData *struct {
+				StartDate int64   `xml:"STARTDATE,attr"`
+				StartTime int64   `xml:"STARTTIME,attr"`
+				Duration  float64 `xml:"DURATION,attr"`
+			} `xml:"EXTENDEDDATA"`
+		} `xml:"ENTRY"`
+	} `xml:"PLAYLISTS>NODE>SUBNODES>NODE>PLAYLIST"`
+}
+
+// Traktor history archives hold a playlist whose entries reference the
+// collection by a "{volume}{dir}{file}" key and carry EXTENDEDDATA with the
+// packed start date (year<<16 | month<<8 | day), the start time in seconds
+// since midnight and the played duration in seconds.
+func parseTraktorNml(content []byte) ([]Entry, error) {
+	var data traktorNml
+	if err := xml.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+	tracksByKey := make(map[string]Entry, len(data.Collection))
+	for _, track := range data.Collection {
+		key := track.Location.Volume + track.Location.Dir + track.Location.File
+		tracksByKey[key] = Entry{
+			Artist:        track.Artist,
+			Title:         track.Title,
+			Album:         track.Album.Title,
+			PlayedSeconds: unknownPlayedSeconds,
+		}
+	}
+	entries := make([]Entry, 0)
+	for _, playlist := range data.Playlists {
+		for _, playlistEntry := range playlist.Entries {
+			entry, ok := tracksByKey[playlistEntry.PrimaryKey.Key]
+			if !ok {
+				continue
+			}
+			if extendedData := playlistEntry.ExtendedData; extendedData != nil {
+				if extendedData.StartDate > 0 {
+					entry.StartTime = time.Date(
+						int(extendedData.StartDate>>16),
+						time.Month((extendedData.StartDate>>8)&0xFF),
+						int(extendedData.StartDate&0xFF),
+						0, 0, 0, 0,
+						time.Local,
+					).Add(time.Duration(extendedData.StartTime) * time.Second)
+				}
+				entry.PlayedSeconds = extendedData.Duration
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
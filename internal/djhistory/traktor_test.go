@@ -0,0 +1,23 @@
+package djhistory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTraktorNml(t *testing.T) {
+	// STARTDATE 132645640 packs 2024-03-08, and STARTTIME counts seconds from
+	// midnight; entries missing from the collection are skipped
+	want := []Entry{
+		{Artist: "The Vancouver Sound", Title: "Northern Lights", Album: "Northern Lights", StartTime: localTime(8, 23, 0, 0), PlayedSeconds: 212.5},
+		{Artist: "Wheatfield", Title: "Grain Elevator", Album: "Prairie Static", StartTime: localTime(8, 23, 3, 30), PlayedSeconds: 4},
+		{Artist: "The Vancouver Sound", Title: "Harbour Fog", Album: "Northern Lights", PlayedSeconds: unknownPlayedSeconds},
+	}
+	got, err := Parse("history_2024y03m08d_23h00m00s.nml", readFixture(t, "traktor_history.nml"), Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse:\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/captaincoordinates/cick-playlister/internal/cancon"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/instrumental"
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+)
+
+const (
+	canConParam = "canCon"
+	showParam   = "show"
+)
+
+var enrichmentParams = []string{canConParam, showParam}
+
+// trackEnrichment adds assessments that do not depend on the provider to
+// tracks once they have been fetched. Those that need lookups of their own
+// are requested by a query parameter, which is validated before the provider
+// is called. Handlers decide whether tracks are new under the station's
+// rules; a show with rules of its own is named by a query parameter. The
+// zero trackEnrichment adds nothing, for tracks that are enriched together
+// once combined.
+type trackEnrichment struct {
+	instrumentalDetector *instrumental.Detector
+	canConAssessor       *cancon.Assessor
+	newReleaseRules      *newrelease.Rules
+}
+
+type enrichmentOptions struct {
+	canCon          bool
+	newReleaseRules *newrelease.Rules
+}
+
+func (enrichment trackEnrichment) newOptions(query url.Values) (enrichmentOptions, error) {
+	var options enrichmentOptions
+	if show := query.Get(showParam); show != "" {
+		showRules, ok := enrichment.newReleaseRules.ForShow(show)
+		if !ok {
+			return options, handler.NewInvalidRequestError(fmt.Sprintf("unknown '%s': %s", showParam, show))
+		}
+		options.newReleaseRules = showRules
+	}
+	if value := query.Get(canConParam); value != "" {
+		canCon, err := strconv.ParseBool(value)
+		if err != nil {
+			return options, handler.NewInvalidRequestError(fmt.Sprintf("'%s' must be true or false", canConParam))
+		}
+		options.canCon = canCon
+	}
+	return options, nil
+}
+
+func (enrichment trackEnrichment) apply(options enrichmentOptions, tracks []handler.TrackInfo) []handler.TrackInfo {
+	if options.newReleaseRules != nil {
+		tracks = options.newReleaseRules.Reapply(tracks)
+	}
+	if enrichment.instrumentalDetector != nil {
+		tracks = enrichment.instrumentalDetector.Apply(tracks)
+	}
+	if options.canCon && enrichment.canConAssessor != nil {
+		tracks = enrichment.canConAssessor.Apply(tracks)
+	}
+	return tracks
+}
+
+func (enrichment trackEnrichment) collection(handlerFunction func(*http.Request) (handler.TrackCollectionInfo, error)) func(*http.Request) (handler.TrackCollectionInfo, error) {
+	return func(request *http.Request) (handler.TrackCollectionInfo, error) {
+		options, err := enrichment.newOptions(request.URL.Query())
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		collectionInfo, err := handlerFunction(request)
+		if err != nil {
+			return collectionInfo, err
+		}
+		collectionInfo.Tracks = enrichment.apply(options, collectionInfo.Tracks)
+		return collectionInfo, nil
+	}
+}
+
+func (enrichment trackEnrichment) track(handlerFunction func(*http.Request) (handler.TrackInfo, error)) func(*http.Request) (handler.TrackInfo, error) {
+	return func(request *http.Request) (handler.TrackInfo, error) {
+		options, err := enrichment.newOptions(request.URL.Query())
+		if err != nil {
+			return handler.EmptyTrackInfo, err
+		}
+		trackInfo, err := handlerFunction(request)
+		if err != nil {
+			return trackInfo, err
+		}
+		return enrichment.apply(options, []handler.TrackInfo{trackInfo})[0], nil
+	}
+}
@@ -0,0 +1,227 @@
+package applemusic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const apiUrlBase = "https://api.music.apple.com"
+const defaultStorefront = "ca"
+const singleAlbumSuffix = " - Single"
+const epAlbumSuffix = " - EP"
+
+// A song shared from an album page is the album link with the song's ID in
+// the "i" query parameter, so it is matched before the album pattern.
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://(?:geo\.)?music\.apple\.com/[a-z]{2}/album/(?:[^/?#]+/)?\d+\?(?:[^#]*&)?i=(\d+)`),
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://(?:geo\.)?music\.apple\.com/[a-z]{2}/song/(?:[^/?#]+/)?(\d+)`),
+	handler.NewUrlPattern(constants.AlbumRequestType, `^https?://(?:geo\.)?music\.apple\.com/[a-z]{2}/album/(?:[^/?#]+/)?(\d+)`),
+	handler.NewUrlPattern(constants.PlaylistRequestType, `^https?://(?:geo\.)?music\.apple\.com/[a-z]{2}/playlist/(?:[^/?#]+/)?(pl\.[\w-]+)`),
+}
+
+func (appleMusicHandler *AppleMusicHandler) Identifier() string {
+	return "applemusic"
+}
+
+func (appleMusicHandler *AppleMusicHandler) UrlPatterns() []handler.UrlPattern {
+	return urlPatterns
+}
+
+func (appleMusicHandler *AppleMusicHandler) Track(request *http.Request) (trackInfo handler.TrackInfo, err error) {
+	vars := appleMusicHandler.pathParamsProvider(request)
+	trackParamValue := vars[constants.TrackIdentifierParam]
+	token, err := appleMusicHandler.getToken(appleMusicHandler.teamId, appleMusicHandler.keyId, appleMusicHandler.privateKey)
+	if token == "" || err != nil {
+		return handler.EmptyTrackInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	url := fmt.Sprintf(
+		"%s/v1/catalog/%s/songs/%s",
+		apiUrlBase,
+		appleMusicHandler.storefront,
+		trackParamValue,
+	)
+	var data AppleMusicSongsData
+	statusCode, err := getJson(url, token, &data)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(trackParamValue)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return handler.EmptyTrackInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	case http.StatusNotFound:
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	default:
+		return handler.EmptyTrackInfo, fmt.Errorf("apple music API returned status: %d", statusCode)
+	}
+	if len(data.Data) == 0 {
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	}
+	return appleMusicHandler.trackInfoFromAppleMusicSongData(data.Data[0]), nil
+}
+
+func (appleMusicHandler *AppleMusicHandler) Album(request *http.Request) (albumInfo handler.TrackCollectionInfo, err error) {
+	vars := appleMusicHandler.pathParamsProvider(request)
+	albumParamValue := vars[constants.AlbumIdentifierParam]
+	token, err := appleMusicHandler.getToken(appleMusicHandler.teamId, appleMusicHandler.keyId, appleMusicHandler.privateKey)
+	if token == "" || err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	url := fmt.Sprintf(
+		"%s/v1/catalog/%s/albums/%s",
+		apiUrlBase,
+		appleMusicHandler.storefront,
+		albumParamValue,
+	)
+	var data AppleMusicAlbumData
+	statusCode, err := getJson(url, token, &data)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	if err := collectionStatusError(statusCode, albumParamValue); err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	if len(data.Data) == 0 {
+		return handler.EmptyTrackCollectionInfo, handler.NewTrackCollectionNotFoundError(albumParamValue)
+	}
+	album := data.Data[0]
+	albumName, _ := albumNameAndIsSingle(album.Attributes.Name)
+	trackInfos := make([]handler.TrackInfo, 0)
+	tracks := album.Relationships.Tracks
+	for {
+		for _, entry := range tracks.Data {
+			trackInfo := handler.NewTrackInfo(
+				entry.Attributes.ArtistName,
+				entry.Attributes.Name,
+				albumName,
+				album.Attributes.IsSingle,
+				false,
+			)
+			trackInfo = appleMusicHandler.newReleaseRules.Apply(trackInfo, album.Attributes.ReleaseDate, handler.ReleaseDatePrecision(album.Attributes.ReleaseDate))
+			trackInfo.Label = album.Attributes.RecordLabel
+			trackInfo.Link = entry.Attributes.Url
+			trackInfo.DiscNumber = entry.Attributes.DiscNumber
+			trackInfo.Duration = handler.SecondsFromMilliseconds(entry.Attributes.DurationInMillis)
+			trackInfos = append(trackInfos, trackInfo)
+		}
+		if tracks.Next == "" {
+			break
+		}
+		nextUrl := fmt.Sprintf("%s%s", apiUrlBase, tracks.Next)
+		tracks = AppleMusicSongsData{}
+		statusCode, err := getJson(nextUrl, token, &tracks)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		if err := collectionStatusError(statusCode, albumParamValue); err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, albumParamValue), nil
+}
+
+func (appleMusicHandler *AppleMusicHandler) Playlist(request *http.Request) (playlistInfo handler.TrackCollectionInfo, err error) {
+	vars := appleMusicHandler.pathParamsProvider(request)
+	playlistParamValue := vars[constants.PlaylistIdentifierParam]
+	token, err := appleMusicHandler.getToken(appleMusicHandler.teamId, appleMusicHandler.keyId, appleMusicHandler.privateKey)
+	if token == "" || err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	nextUrl := fmt.Sprintf(
+		"%s/v1/catalog/%s/playlists/%s/tracks?limit=100",
+		apiUrlBase,
+		appleMusicHandler.storefront,
+		playlistParamValue,
+	)
+	trackInfos := make([]handler.TrackInfo, 0)
+	for nextUrl != "" {
+		var data AppleMusicSongsData
+		statusCode, err := getJson(nextUrl, token, &data)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		if err := collectionStatusError(statusCode, playlistParamValue); err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		for _, entry := range data.Data {
+			trackInfos = append(
+				trackInfos,
+				appleMusicHandler.trackInfoFromAppleMusicSongData(entry),
+			)
+		}
+		nextUrl = ""
+		if data.Next != "" {
+			nextUrl = fmt.Sprintf("%s%s", apiUrlBase, data.Next)
+		}
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, playlistParamValue), nil
+}
+
+func (appleMusicHandler *AppleMusicHandler) trackInfoFromAppleMusicSongData(appleMusicSongData AppleMusicSongData) handler.TrackInfo {
+	albumName, isSingle := albumNameAndIsSingle(appleMusicSongData.Attributes.AlbumName)
+	trackInfo := handler.NewTrackInfo(
+		appleMusicSongData.Attributes.ArtistName,
+		appleMusicSongData.Attributes.Name,
+		albumName,
+		isSingle,
+		false,
+	)
+	trackInfo = appleMusicHandler.newReleaseRules.Apply(trackInfo, appleMusicSongData.Attributes.ReleaseDate, handler.ReleaseDatePrecision(appleMusicSongData.Attributes.ReleaseDate))
+	trackInfo.Link = appleMusicSongData.Attributes.Url
+	trackInfo.DiscNumber = appleMusicSongData.Attributes.DiscNumber
+	trackInfo.Duration = handler.SecondsFromMilliseconds(appleMusicSongData.Attributes.DurationInMillis)
+	return trackInfo
+}
+
+// Song resources do not carry their album's isSingle attribute, but Apple Music
+// consistently suffixes single and EP release names in albumName.
+func albumNameAndIsSingle(albumName string) (string, bool) {
+	if strings.HasSuffix(albumName, singleAlbumSuffix) {
+		return strings.TrimSuffix(albumName, singleAlbumSuffix), true
+	}
+	return strings.TrimSuffix(albumName, epAlbumSuffix), false
+}
+
+func collectionStatusError(statusCode int, collectionId string) error {
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusBadRequest:
+		return handler.NewInvalidTrackCollectionIdError(collectionId)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	case http.StatusNotFound:
+		return handler.NewTrackCollectionNotFoundError(collectionId)
+	default:
+		return fmt.Errorf("apple music API returned status: %d", statusCode)
+	}
+}
+
+func getJson(url string, token string, target any) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, handler.NewInternalError(err.Error())
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, json.Unmarshal(body, target)
+}
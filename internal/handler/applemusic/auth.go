@@ -0,0 +1,117 @@
+package applemusic
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const developerTokenLifetimeSeconds int64 = 60 * 60 * 12
+
+type AppleMusicTokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type AppleMusicTokenClaims struct {
+	Issuer    string `json:"iss"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+func (appleMusicHandler *AppleMusicHandler) getToken(teamId string, keyId string, privateKeyPem string) (string, error) {
+	appleMusicHandler.tokenMutex.Lock()
+	defer appleMusicHandler.tokenMutex.Unlock()
+	nowMilli := time.Now().UTC().UnixMilli()
+	if appleMusicHandler.tokenExpiryTimeMilli-nowMilli <= 5000 {
+		if teamId == "" || keyId == "" || privateKeyPem == "" {
+			return "", errors.New("credentials for Apple Music are not configured")
+		}
+		privateKey, err := parsePrivateKey(privateKeyPem)
+		if err != nil {
+			return "", err
+		}
+		issuedAt := nowMilli / 1000
+		header, err := json.Marshal(AppleMusicTokenHeader{
+			Algorithm: "ES256",
+			KeyID:     keyId,
+		})
+		if err != nil {
+			return "", err
+		}
+		claims, err := json.Marshal(AppleMusicTokenClaims{
+			Issuer:    teamId,
+			IssuedAt:  issuedAt,
+			ExpiresAt: issuedAt + developerTokenLifetimeSeconds,
+		})
+		if err != nil {
+			return "", err
+		}
+		signingInput := fmt.Sprintf(
+			"%s.%s",
+			base64.RawURLEncoding.EncodeToString(header),
+			base64.RawURLEncoding.EncodeToString(claims),
+		)
+		digest := sha256.Sum256([]byte(signingInput))
+		r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
+		if err != nil {
+			return "", err
+		}
+		// JWS ES256 signatures are the fixed-width concatenation of r and s, not ASN.1
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		appleMusicHandler.token = fmt.Sprintf(
+			"%s.%s",
+			signingInput,
+			base64.RawURLEncoding.EncodeToString(signature),
+		)
+		appleMusicHandler.tokenExpiryTimeMilli = nowMilli + developerTokenLifetimeSeconds*1000
+	}
+	return appleMusicHandler.token, nil
+}
+
+func parsePrivateKey(privateKeyPem string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(privateKeyPem)))
+	if block == nil {
+		return nil, errors.New("private key for Apple Music is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key for Apple Music is not an ECDSA key")
+	}
+	return ecdsaKey, nil
+}
+
+// ValidateCredentials reports whether Apple Music accepts the signed developer
+// token, which proves the team ID, key ID and private key belong together.
+func (appleMusicHandler *AppleMusicHandler) ValidateCredentials() error {
+	token, err := appleMusicHandler.getToken(appleMusicHandler.teamId, appleMusicHandler.keyId, appleMusicHandler.privateKey)
+	if token == "" || err != nil {
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	var data any
+	statusCode, err := getJson(fmt.Sprintf("%s/v1/storefronts/%s", apiUrlBase, appleMusicHandler.storefront), token, &data)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusOK {
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	return nil
+}
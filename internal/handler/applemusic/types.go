@@ -0,0 +1,79 @@
+package applemusic
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type AppleMusicSongData struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Name             string `json:"name"`
+		ArtistName       string `json:"artistName"`
+		AlbumName        string `json:"albumName"`
+		ReleaseDate      string `json:"releaseDate"`
+		DiscNumber       int    `json:"discNumber"`
+		DurationInMillis int64  `json:"durationInMillis"`
+		Url              string `json:"url"`
+	} `json:"attributes"`
+}
+
+type AppleMusicSongsData struct {
+	Next string               `json:"next"`
+	Data []AppleMusicSongData `json:"data"`
+}
+
+type AppleMusicAlbumData struct {
+	Data []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Name        string `json:"name"`
+			ArtistName  string `json:"artistName"`
+			ReleaseDate string `json:"releaseDate"`
+			IsSingle    bool   `json:"isSingle"`
+			RecordLabel string `json:"recordLabel"`
+		} `json:"attributes"`
+		Relationships struct {
+			Tracks AppleMusicSongsData `json:"tracks"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+type AppleMusicHandler struct {
+	teamId               string
+	keyId                string
+	privateKey           string
+	storefront           string
+	token                string
+	tokenExpiryTimeMilli int64
+	tokenMutex           sync.Mutex
+	pathParamsProvider   func(*http.Request) map[string]string
+	newReleaseRules      *newrelease.Rules
+}
+
+func NewAppleMusicHandler(
+	teamId string,
+	keyId string,
+	privateKey string,
+	storefront string,
+	newReleaseRules *newrelease.Rules,
+) *AppleMusicHandler {
+	if storefront == "" {
+		storefront = defaultStorefront
+	}
+	return &AppleMusicHandler{
+		teamId:               teamId,
+		keyId:                keyId,
+		privateKey:           privateKey,
+		storefront:           storefront,
+		token:                "",
+		tokenExpiryTimeMilli: 0,
+		pathParamsProvider:   mux.Vars,
+		newReleaseRules:      newReleaseRules,
+	}
+}
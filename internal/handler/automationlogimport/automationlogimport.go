@@ -0,0 +1,84 @@
+package automationlogimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/automationlog"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const (
+	uploadFileField    = "file"
+	formatParam        = "format"
+	mappingParam       = "mapping"
+	dateParam          = "date"
+	maximumUploadBytes = 8 * 1024 * 1024
+)
+
+func (automationLogImportHandler *AutomationLogImportHandler) Identifier() string {
+	return "automationlog"
+}
+
+// Upload reads an as-run log export using a named mapping ("format"), a JSON
+// mapping ("mapping"), or a named mapping with JSON overrides.
+func (automationLogImportHandler *AutomationLogImportHandler) Upload(request *http.Request) (uploadInfo handler.TrackCollectionInfo, err error) {
+	if err := request.ParseMultipartForm(maximumUploadBytes); err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	defer request.MultipartForm.RemoveAll()
+	mapping := automationlog.Mapping{}
+	if format := request.FormValue(formatParam); format != "" {
+		namedMapping, ok := automationLogImportHandler.mappings[format]
+		if !ok {
+			return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(fmt.Sprintf("unknown %s '%s'", formatParam, format))
+		}
+		mapping = namedMapping
+	}
+	if mappingValue := request.FormValue(mappingParam); mappingValue != "" {
+		var override automationlog.MappingOverride
+		if err := json.Unmarshal([]byte(mappingValue), &override); err != nil {
+			return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(fmt.Sprintf("invalid %s: %s", mappingParam, err.Error()))
+		}
+		mapping = mapping.Merge(override)
+	}
+	var logDate time.Time
+	if dateValue := request.FormValue(dateParam); dateValue != "" {
+		logDate, err = time.ParseInLocation(time.DateOnly, dateValue, time.Local)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(fmt.Sprintf("invalid %s '%s'", dateParam, dateValue))
+		}
+	}
+	file, fileHeader, err := request.FormFile(uploadFileField)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInternalError(err.Error())
+	}
+	entries, err := automationlog.Parse(content, mapping, logDate)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	trackInfos := make([]handler.TrackInfo, 0, len(entries))
+	for _, entry := range entries {
+		trackInfo := handler.NewTrackInfo(
+			entry.Artist,
+			entry.Title,
+			entry.Album,
+			false,
+			false,
+		)
+		if !entry.StartTime.IsZero() {
+			startTime := entry.StartTime
+			trackInfo.StartTime = &startTime
+		}
+		trackInfos = append(trackInfos, automationLogImportHandler.newReleaseRules.Apply(trackInfo, "", ""))
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, fileHeader.Filename), nil
+}
@@ -0,0 +1,21 @@
+package automationlogimport
+
+import (
+	"github.com/captaincoordinates/cick-playlister/internal/automationlog"
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+)
+
+type AutomationLogImportHandler struct {
+	mappings        map[string]automationlog.Mapping
+	newReleaseRules *newrelease.Rules
+}
+
+func NewAutomationLogImportHandler(
+	mappings map[string]automationlog.Mapping,
+	newReleaseRules *newrelease.Rules,
+) *AutomationLogImportHandler {
+	return &AutomationLogImportHandler{
+		mappings:        mappings,
+		newReleaseRules: newReleaseRules,
+	}
+}
@@ -0,0 +1,183 @@
+package bandcamp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const (
+	maximumPageBytes     = 8 * 1024 * 1024
+	maximumPageRedirects = 5
+	pageTimeout          = 20 * time.Second
+)
+
+var artistSubdomainRegex = regexp.MustCompile(`^[\w-]+$`)
+
+var errRedirectNotAllowed = errors.New("bandcamp page redirected to a host that is not allowed")
+
+// Artists on custom domains are only recognised, and their pages only
+// fetched, when the domain is configured.
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPattern(constants.AlbumRequestType, `^https?://([\w-]+)\.bandcamp\.com/album/([\w-]+)`),
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://([\w-]+)\.bandcamp\.com/track/([\w-]+)`),
+}
+
+func customDomainUrlPatterns(customDomains []string) []handler.UrlPattern {
+	patterns := slices.Clone(urlPatterns)
+	for _, domain := range customDomains {
+		host := regexp.QuoteMeta(domain)
+		patterns = append(
+			patterns,
+			handler.NewUrlPattern(constants.AlbumRequestType, fmt.Sprintf(`^https?://(%s)/album/([\w-]+)`, host)),
+			handler.NewUrlPattern(constants.TrackRequestType, fmt.Sprintf(`^https?://(%s)/track/([\w-]+)`, host)),
+		)
+	}
+	return patterns
+}
+
+func (bandcampHandler *BandcampHandler) Identifier() string {
+	return "bandcamp"
+}
+
+func (bandcampHandler *BandcampHandler) UrlPatterns() []handler.UrlPattern {
+	return bandcampHandler.urlPatterns
+}
+
+func (bandcampHandler *BandcampHandler) Track(request *http.Request) (trackInfo handler.TrackInfo, err error) {
+	vars := bandcampHandler.pathParamsProvider(request)
+	trackParamValue := vars[constants.TrackIdentifierParam]
+	url, ok := bandcampHandler.pageUrl(trackItemType, trackParamValue)
+	if !ok {
+		return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(trackParamValue)
+	}
+	statusCode, page, err := bandcampHandler.getPage(url)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	default:
+		return handler.EmptyTrackInfo, fmt.Errorf("bandcamp returned status: %d", statusCode)
+	}
+	release, err := parseReleasePage(page)
+	if err != nil || release.itemType != trackItemType || len(release.tracks) == 0 {
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	}
+	return bandcampHandler.trackInfoFromBandcampRelease(release, release.tracks[0]), nil
+}
+
+func (bandcampHandler *BandcampHandler) Album(request *http.Request) (albumInfo handler.TrackCollectionInfo, err error) {
+	vars := bandcampHandler.pathParamsProvider(request)
+	albumParamValue := vars[constants.AlbumIdentifierParam]
+	url, ok := bandcampHandler.pageUrl(albumItemType, albumParamValue)
+	if !ok {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(albumParamValue)
+	}
+	statusCode, page, err := bandcampHandler.getPage(url)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return handler.EmptyTrackCollectionInfo, handler.NewTrackCollectionNotFoundError(albumParamValue)
+	default:
+		return handler.EmptyTrackCollectionInfo, fmt.Errorf("bandcamp returned status: %d", statusCode)
+	}
+	release, err := parseReleasePage(page)
+	if err != nil || release.itemType != albumItemType {
+		return handler.EmptyTrackCollectionInfo, handler.NewTrackCollectionNotFoundError(albumParamValue)
+	}
+	trackInfos := make([]handler.TrackInfo, len(release.tracks))
+	for i, track := range release.tracks {
+		trackInfos[i] = bandcampHandler.trackInfoFromBandcampRelease(release, track)
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, albumParamValue), nil
+}
+
+func (bandcampHandler *BandcampHandler) trackInfoFromBandcampRelease(release bandcampRelease, track bandcampTrack) handler.TrackInfo {
+	album := release.album
+	if album == "" {
+		album = track.title
+	}
+	trackInfo := handler.NewTrackInfo(
+		track.artist,
+		track.title,
+		album,
+		release.trackCount == 1,
+		false,
+	)
+	trackInfo = bandcampHandler.newReleaseRules.Apply(trackInfo, release.releaseDate, handler.ReleaseDatePrecisionDay)
+	trackInfo.Duration = track.duration
+	return trackInfo
+}
+
+// Identifiers take the form "{artist}/{slug}" for artist.bandcamp.com pages,
+// or "{host}/{slug}" for artists on a configured custom domain.
+func (bandcampHandler *BandcampHandler) pageUrl(itemType string, identifier string) (string, bool) {
+	parts := strings.Split(strings.Trim(identifier, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	host := strings.ToLower(parts[0])
+	if !strings.Contains(host, ".") {
+		host = fmt.Sprintf("%s.bandcamp.com", host)
+	}
+	if !bandcampHandler.isAllowedHost(host) {
+		return "", false
+	}
+	return fmt.Sprintf(
+		"https://%s/%s/%s",
+		host,
+		itemType,
+		parts[1],
+	), true
+}
+
+func (bandcampHandler *BandcampHandler) isAllowedHost(host string) bool {
+	host = strings.ToLower(host)
+	if subdomain, ok := strings.CutSuffix(host, ".bandcamp.com"); ok {
+		return artistSubdomainRegex.MatchString(subdomain)
+	}
+	return slices.Contains(bandcampHandler.customDomains, host)
+}
+
+// Redirects are followed only to allowed hosts, and pages are read up to a
+// limit as custom domains are not Bandcamp's own servers.
+func (bandcampHandler *BandcampHandler) getPage(url string) (int, []byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, handler.NewInternalError(err.Error())
+	}
+	req.Header.Add("User-Agent", constants.UserAgent)
+	client := &http.Client{
+		Timeout: pageTimeout,
+		CheckRedirect: func(redirect *http.Request, via []*http.Request) error {
+			if len(via) >= maximumPageRedirects || redirect.URL.Scheme != "https" || redirect.URL.Port() != "" || !bandcampHandler.isAllowedHost(redirect.URL.Hostname()) {
+				return errRedirectNotAllowed
+			}
+			return nil
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maximumPageBytes))
+	return resp.StatusCode, body, err
+}
@@ -0,0 +1,147 @@
+package bandcamp
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const (
+	albumItemType = "album"
+	trackItemType = "track"
+)
+
+const bandcampDateLayout = "02 Jan 2006 15:04:05 MST"
+
+var ldJsonRegex = regexp.MustCompile(`(?s)<script[^>]+type="application/ld\+json"[^>]*>(.*?)</script>`)
+var tralbumRegex = regexp.MustCompile(`data-tralbum="([^"]*)"`)
+var embedRegex = regexp.MustCompile(`data-embed="([^"]*)"`)
+
+var errNoReleaseData = errors.New("no release metadata found in Bandcamp page")
+
+// Album and track pages embed the same release in two forms. The ld+json
+// block is the documented one and is preferred; the data-tralbum attribute
+// backs it up on older pages that do not carry ld+json.
+func parseReleasePage(page []byte) (bandcampRelease, error) {
+	if release, ok := parseLdJson(page); ok {
+		return release, nil
+	}
+	if release, ok := parseTralbum(page); ok {
+		return release, nil
+	}
+	return bandcampRelease{}, errNoReleaseData
+}
+
+func parseLdJson(page []byte) (bandcampRelease, bool) {
+	match := ldJsonRegex.FindSubmatch(page)
+	if match == nil {
+		return bandcampRelease{}, false
+	}
+	var data BandcampLdJsonData
+	if err := json.Unmarshal(match[1], &data); err != nil {
+		return bandcampRelease{}, false
+	}
+	release := bandcampRelease{
+		title:       data.Name,
+		releaseDate: releaseDateFromBandcampDate(data.DatePublished),
+	}
+	if data.ByArtist != nil {
+		release.artist = data.ByArtist.Name
+	}
+	switch data.Type {
+	case "MusicAlbum":
+		release.itemType = albumItemType
+		release.album = data.Name
+		if data.Track != nil {
+			for _, element := range data.Track.ItemListElement {
+				artist := release.artist
+				if element.Item.ByArtist != nil && element.Item.ByArtist.Name != "" {
+					artist = element.Item.ByArtist.Name
+				}
+				release.tracks = append(release.tracks, bandcampTrack{
+					artist:   artist,
+					title:    element.Item.Name,
+					duration: handler.SecondsFromIsoDuration(element.Item.Duration),
+				})
+			}
+		}
+		release.trackCount = max(data.NumTracks, len(release.tracks))
+	case "MusicRecording":
+		release.itemType = trackItemType
+		release.tracks = []bandcampTrack{{
+			artist:   release.artist,
+			title:    data.Name,
+			duration: handler.SecondsFromIsoDuration(data.Duration),
+		}}
+		release.trackCount = 1
+		if data.InAlbum != nil && data.InAlbum.Name != "" {
+			release.album = data.InAlbum.Name
+			release.trackCount = data.InAlbum.NumTracks
+		}
+	default:
+		return bandcampRelease{}, false
+	}
+	return release, release.title != ""
+}
+
+func parseTralbum(page []byte) (bandcampRelease, bool) {
+	match := tralbumRegex.FindSubmatch(page)
+	if match == nil {
+		return bandcampRelease{}, false
+	}
+	var data BandcampTralbumData
+	if err := json.Unmarshal([]byte(html.UnescapeString(string(match[1]))), &data); err != nil {
+		return bandcampRelease{}, false
+	}
+	releaseDate := data.AlbumReleaseDate
+	if releaseDate == "" {
+		releaseDate = data.Current.ReleaseDate
+	}
+	release := bandcampRelease{
+		itemType:    data.ItemType,
+		artist:      data.Artist,
+		title:       data.Current.Title,
+		releaseDate: releaseDateFromBandcampDate(releaseDate),
+	}
+	for _, entry := range data.TrackInfo {
+		artist := data.Artist
+		if entry.Artist != "" {
+			artist = entry.Artist
+		}
+		release.tracks = append(release.tracks, bandcampTrack{
+			artist:   artist,
+			title:    entry.Title,
+			duration: int(math.Round(entry.Duration)),
+		})
+	}
+	switch data.ItemType {
+	case albumItemType:
+		release.album = release.title
+		release.trackCount = len(release.tracks)
+	case trackItemType:
+		release.trackCount = 1
+		if embedMatch := embedRegex.FindSubmatch(page); embedMatch != nil {
+			var embed BandcampEmbedData
+			if err := json.Unmarshal([]byte(html.UnescapeString(string(embedMatch[1]))), &embed); err == nil && embed.AlbumTitle != "" {
+				release.album = embed.AlbumTitle
+				release.trackCount = 0
+			}
+		}
+	default:
+		return bandcampRelease{}, false
+	}
+	return release, release.title != ""
+}
+
+func releaseDateFromBandcampDate(bandcampDate string) string {
+	date, err := time.Parse(bandcampDateLayout, bandcampDate)
+	if err != nil {
+		return ""
+	}
+	return date.UTC().Format(time.DateOnly)
+}
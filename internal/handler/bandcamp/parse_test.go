@@ -0,0 +1,146 @@
+package bandcamp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	page, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture %s: %v", name, err)
+	}
+	return page
+}
+
+var ldJsonAlbumRelease = bandcampRelease{
+	itemType:    albumItemType,
+	artist:      "The Vancouver Sound",
+	title:       "Northern Lights",
+	album:       "Northern Lights",
+	releaseDate: "2023-03-14",
+	trackCount:  3,
+	tracks: []bandcampTrack{
+		{artist: "The Vancouver Sound", title: "Aurora", duration: 205},
+		{artist: "The Vancouver Sound feat. Kim", title: "Harbour Fog", duration: 242},
+		{artist: "The Vancouver Sound", title: "Last Ferry", duration: 310},
+	},
+}
+
+var ldJsonTrackRelease = bandcampRelease{
+	itemType:    trackItemType,
+	artist:      "The Vancouver Sound",
+	title:       "Harbour Fog",
+	album:       "Northern Lights",
+	releaseDate: "2023-03-14",
+	trackCount:  3,
+	tracks: []bandcampTrack{
+		{artist: "The Vancouver Sound", title: "Harbour Fog", duration: 242},
+	},
+}
+
+var tralbumAlbumRelease = bandcampRelease{
+	itemType:    albumItemType,
+	artist:      "Wheatfield",
+	title:       "Prairie Static",
+	album:       "Prairie Static",
+	releaseDate: "2011-06-02",
+	trackCount:  3,
+	tracks: []bandcampTrack{
+		{artist: "Wheatfield", title: "Grain Elevator", duration: 181},
+		{artist: "Wheatfield & Friends", title: "Dust & Wire", duration: 240},
+		{artist: "Wheatfield", title: "Long Road Home", duration: 300},
+	},
+}
+
+var tralbumTrackRelease = bandcampRelease{
+	itemType:    trackItemType,
+	artist:      "Wheatfield",
+	title:       "Dust & Wire",
+	album:       "Prairie Static",
+	releaseDate: "2011-06-02",
+	trackCount:  0,
+	tracks: []bandcampTrack{
+		{artist: "Wheatfield", title: "Dust & Wire", duration: 240},
+	},
+}
+
+func TestParseReleasePage(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    bandcampRelease
+	}{
+		{"album_ldjson.html", ldJsonAlbumRelease},
+		{"track_ldjson.html", ldJsonTrackRelease},
+		{"album_tralbum.html", tralbumAlbumRelease},
+		{"track_tralbum.html", tralbumTrackRelease},
+	}
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			got, err := parseReleasePage(readFixture(t, test.fixture))
+			if err != nil {
+				t.Fatalf("parseReleasePage: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseReleasePage:\n got %+v\nwant %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseReleasePageWithoutMetadata(t *testing.T) {
+	if _, err := parseReleasePage([]byte("<html><body></body></html>")); err != errNoReleaseData {
+		t.Errorf("parseReleasePage: got error %v, want %v", err, errNoReleaseData)
+	}
+}
+
+func TestParseLdJson(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    bandcampRelease
+		ok      bool
+	}{
+		{"album_ldjson.html", ldJsonAlbumRelease, true},
+		{"track_ldjson.html", ldJsonTrackRelease, true},
+		{"album_tralbum.html", bandcampRelease{}, false},
+		{"track_tralbum.html", bandcampRelease{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			got, ok := parseLdJson(readFixture(t, test.fixture))
+			if ok != test.ok {
+				t.Fatalf("parseLdJson: got ok %t, want %t", ok, test.ok)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseLdJson:\n got %+v\nwant %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseTralbum(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    bandcampRelease
+		ok      bool
+	}{
+		{"album_tralbum.html", tralbumAlbumRelease, true},
+		{"track_tralbum.html", tralbumTrackRelease, true},
+		{"album_ldjson.html", bandcampRelease{}, false},
+		{"track_ldjson.html", bandcampRelease{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			got, ok := parseTralbum(readFixture(t, test.fixture))
+			if ok != test.ok {
+				t.Fatalf("parseTralbum: got ok %t, want %t", ok, test.ok)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseTralbum:\n got %+v\nwant %+v", got, test.want)
+			}
+		})
+	}
+}
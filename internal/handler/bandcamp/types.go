@@ -0,0 +1,92 @@
+package bandcamp
+
+import (
+	"net/http"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type BandcampArtistData struct {
+	Name string `json:"name"`
+}
+
+type BandcampLdJsonData struct {
+	Type          string              `json:"@type"`
+	Name          string              `json:"name"`
+	DatePublished string              `json:"datePublished"`
+	Duration      string              `json:"duration"`
+	ByArtist      *BandcampArtistData `json:"byArtist"`
+	NumTracks     int                 `json:"numTracks"`
+	InAlbum       *struct {
+		Name      string              `json:"name"`
+		NumTracks int                 `json:"numTracks"`
+		ByArtist  *BandcampArtistData `json:"byArtist"`
+	} `json:"inAlbum"`
+	Track *struct {
+		ItemListElement []struct {
+			Position int `json:"position"`
+			Item     struct {
+				Name     string              `json:"name"`
+				Duration string              `json:"duration"`
+				ByArtist *BandcampArtistData `json:"byArtist"`
+			} `json:"item"`
+		} `json:"itemListElement"`
+	} `json:"track"`
+}
+
+type BandcampTralbumData struct {
+	Artist           string `json:"artist"`
+	ItemType         string `json:"item_type"`
+	AlbumReleaseDate string `json:"album_release_date"`
+	Current          struct {
+		Title       string `json:"title"`
+		ReleaseDate string `json:"release_date"`
+	} `json:"current"`
+	TrackInfo []struct {
+		Title    string  `json:"title"`
+		Artist   string  `json:"artist"`
+		Duration float64 `json:"duration"`
+	} `json:"trackinfo"`
+}
+
+type BandcampEmbedData struct {
+	AlbumTitle string `json:"album_title"`
+}
+
+type bandcampRelease struct {
+	itemType    string
+	artist      string
+	title       string
+	album       string
+	releaseDate string
+	trackCount  int
+	tracks      []bandcampTrack
+}
+
+type bandcampTrack struct {
+	artist   string
+	title    string
+	duration int
+}
+
+type BandcampHandler struct {
+	customDomains      []string
+	urlPatterns        []handler.UrlPattern
+	pathParamsProvider func(*http.Request) map[string]string
+	newReleaseRules    *newrelease.Rules
+}
+
+func NewBandcampHandler(
+	customDomains []string,
+	newReleaseRules *newrelease.Rules,
+) *BandcampHandler {
+	return &BandcampHandler{
+		customDomains:      customDomains,
+		urlPatterns:        customDomainUrlPatterns(customDomains),
+		pathParamsProvider: mux.Vars,
+		newReleaseRules:    newReleaseRules,
+	}
+}
@@ -0,0 +1,245 @@
+package deezer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const (
+	apiUrlBase     = "https://api.deezer.com"
+	albumCacheSize = 1000
+)
+
+const (
+	deezerParameterErrorCode = 500
+	deezerNotFoundErrorCode  = 800
+)
+
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPattern(constants.PlaylistRequestType, `^https?://(?:www\.)?deezer\.com/(?:[a-z]{2}/)?playlist/(\d+)`),
+	handler.NewUrlPattern(constants.AlbumRequestType, `^https?://(?:www\.)?deezer\.com/(?:[a-z]{2}/)?album/(\d+)`),
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://(?:www\.)?deezer\.com/(?:[a-z]{2}/)?track/(\d+)`),
+}
+
+func (deezerHandler *DeezerHandler) Identifier() string {
+	return "deezer"
+}
+
+func (deezerHandler *DeezerHandler) UrlPatterns() []handler.UrlPattern {
+	return urlPatterns
+}
+
+func (deezerHandler *DeezerHandler) Track(request *http.Request) (trackInfo handler.TrackInfo, err error) {
+	vars := deezerHandler.pathParamsProvider(request)
+	trackParamValue := vars[constants.TrackIdentifierParam]
+	url := fmt.Sprintf(
+		"%s/track/%s",
+		apiUrlBase,
+		trackParamValue,
+	)
+	var data DeezerTrackData
+	err = getJson(url, &data)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	if data.Error != nil {
+		switch data.Error.Code {
+		case deezerParameterErrorCode:
+			return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(trackParamValue)
+		case deezerNotFoundErrorCode:
+			return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+		default:
+			return handler.EmptyTrackInfo, apiError(data.Error.Code, data.Error.Message)
+		}
+	}
+	album, _ := deezerHandler.albumOrSummary(data, true)
+	return deezerHandler.trackInfoFromDeezerData(data, album), nil
+}
+
+func (deezerHandler *DeezerHandler) Album(request *http.Request) (albumInfo handler.TrackCollectionInfo, err error) {
+	vars := deezerHandler.pathParamsProvider(request)
+	albumParamValue := vars[constants.AlbumIdentifierParam]
+	url := fmt.Sprintf(
+		"%s/album/%s",
+		apiUrlBase,
+		albumParamValue,
+	)
+	var data DeezerAlbumData
+	err = getJson(url, &data)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	if err := collectionError(data.DeezerErrorData, albumParamValue); err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	trackInfos := make([]handler.TrackInfo, 0)
+	for _, entry := range data.Tracks.Data {
+		trackInfos = append(
+			trackInfos,
+			deezerHandler.trackInfoFromDeezerData(entry, data),
+		)
+	}
+	nextUrl := data.Tracks.Next
+	for nextUrl != "" {
+		var page DeezerPlaylistData
+		err = getJson(nextUrl, &page)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		if err := collectionError(page.DeezerErrorData, albumParamValue); err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		for _, entry := range page.Data {
+			trackInfos = append(
+				trackInfos,
+				deezerHandler.trackInfoFromDeezerData(entry, data),
+			)
+		}
+		nextUrl = page.Next
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, albumParamValue), nil
+}
+
+func (deezerHandler *DeezerHandler) Playlist(request *http.Request) (playlistInfo handler.TrackCollectionInfo, err error) {
+	vars := deezerHandler.pathParamsProvider(request)
+	playlistParamValue := vars[constants.PlaylistIdentifierParam]
+	nextUrl := fmt.Sprintf(
+		"%s/playlist/%s/tracks",
+		apiUrlBase,
+		playlistParamValue,
+	)
+	trackInfos := make([]handler.TrackInfo, 0)
+	lookupAlbums := true
+	for nextUrl != "" {
+		var data DeezerPlaylistData
+		err = getJson(nextUrl, &data)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		if err := collectionError(data.DeezerErrorData, playlistParamValue); err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		for _, entry := range data.Data {
+			var album DeezerAlbumData
+			album, lookupAlbums = deezerHandler.albumOrSummary(entry, lookupAlbums)
+			trackInfos = append(
+				trackInfos,
+				deezerHandler.trackInfoFromDeezerData(entry, album),
+			)
+		}
+		nextUrl = data.Next
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, playlistParamValue), nil
+}
+
+// albumOrSummary falls back to the album summary carried by the track, which
+// has no record type, release date or label, if the full album cannot be
+// fetched, e.g. because it was removed or the API's rate limit was reached.
+// Once a lookup fails no more are attempted for the request, so a rate
+// limited playlist does not make one failing call per entry; cached albums
+// are still used.
+func (deezerHandler *DeezerHandler) albumOrSummary(deezerTrackData DeezerTrackData, lookup bool) (DeezerAlbumData, bool) {
+	if album, ok := deezerHandler.albumCache.Get(deezerTrackData.Album.ID); ok {
+		return album, lookup
+	}
+	if lookup {
+		if album, err := deezerHandler.getAlbum(deezerTrackData.Album.ID); err == nil {
+			return album, true
+		}
+	}
+	return DeezerAlbumData{
+		ID:    deezerTrackData.Album.ID,
+		Title: deezerTrackData.Album.Title,
+	}, false
+}
+
+// Track and playlist entries only carry a summary of their album, without
+// record_type or release_date, so full album data is fetched once and reused
+// while it remains among the most recently used albums.
+func (deezerHandler *DeezerHandler) getAlbum(albumId int64) (DeezerAlbumData, error) {
+	album, ok := deezerHandler.albumCache.Get(albumId)
+	if ok {
+		return album, nil
+	}
+	url := fmt.Sprintf(
+		"%s/album/%d",
+		apiUrlBase,
+		albumId,
+	)
+	err := getJson(url, &album)
+	if err != nil {
+		return DeezerAlbumData{}, err
+	}
+	if album.Error != nil {
+		return DeezerAlbumData{}, apiError(album.Error.Code, album.Error.Message)
+	}
+	album.Tracks.Data = nil
+	deezerHandler.albumCache.Add(albumId, album)
+	return album, nil
+}
+
+func (deezerHandler *DeezerHandler) trackInfoFromDeezerData(deezerTrackData DeezerTrackData, deezerAlbumData DeezerAlbumData) handler.TrackInfo {
+	trackInfo := handler.NewTrackInfo(
+		deezerTrackData.Artist.Name,
+		deezerTrackData.Title,
+		deezerAlbumData.Title,
+		deezerAlbumData.RecordType == "single",
+		false,
+	)
+	trackInfo = deezerHandler.newReleaseRules.Apply(trackInfo, deezerAlbumData.ReleaseDate, handler.ReleaseDatePrecision(deezerAlbumData.ReleaseDate))
+	trackInfo.Label = deezerAlbumData.Label
+	trackInfo.Link = deezerTrackData.Link
+	trackInfo.DiscNumber = deezerTrackData.DiskNumber
+	trackInfo.Duration = deezerTrackData.Duration
+	if deezerTrackData.TimeAdd > 0 {
+		addedAt := time.Unix(deezerTrackData.TimeAdd, 0)
+		trackInfo.AddedAt = &addedAt
+	}
+	return trackInfo
+}
+
+func collectionError(errorData DeezerErrorData, collectionId string) error {
+	if errorData.Error == nil {
+		return nil
+	}
+	switch errorData.Error.Code {
+	case deezerParameterErrorCode:
+		return handler.NewInvalidTrackCollectionIdError(collectionId)
+	case deezerNotFoundErrorCode:
+		return handler.NewTrackCollectionNotFoundError(collectionId)
+	default:
+		return apiError(errorData.Error.Code, errorData.Error.Message)
+	}
+}
+
+func apiError(code int, message string) error {
+	return handler.NewUpstreamError("Deezer", fmt.Sprintf("%d %s", code, message))
+}
+
+// The Deezer API reports errors in the body of a 200 response, so non-200
+// statuses indicate a problem reaching the API rather than with the request.
+func getJson(url string, target any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return handler.NewInternalError(err.Error())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return handler.NewUpstreamError("Deezer", err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return handler.NewUpstreamError("Deezer", fmt.Sprintf("status %d", resp.StatusCode))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, target)
+}
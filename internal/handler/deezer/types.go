@@ -0,0 +1,70 @@
+package deezer
+
+import (
+	"net/http"
+
+	"github.com/captaincoordinates/cick-playlister/internal/cache"
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type DeezerErrorData struct {
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error"`
+}
+
+type DeezerTrackData struct {
+	DeezerErrorData
+	Title      string `json:"title"`
+	Link       string `json:"link"`
+	DiskNumber int    `json:"disk_number"`
+	Duration   int    `json:"duration"`
+	// only present on playlist entries, in seconds since the epoch
+	TimeAdd int64 `json:"time_add"`
+	Artist  struct {
+		Name string `json:"name"`
+	} `json:"artist"`
+	Album struct {
+		ID    int64  `json:"id"`
+		Title string `json:"title"`
+	} `json:"album"`
+}
+
+type DeezerPlaylistData struct {
+	DeezerErrorData
+	Next string            `json:"next"`
+	Data []DeezerTrackData `json:"data"`
+}
+
+type DeezerAlbumData struct {
+	DeezerErrorData
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Label       string `json:"label"`
+	ReleaseDate string `json:"release_date"`
+	RecordType  string `json:"record_type"`
+	Tracks      struct {
+		Next string            `json:"next"`
+		Data []DeezerTrackData `json:"data"`
+	} `json:"tracks"`
+}
+
+type DeezerHandler struct {
+	albumCache         *cache.LRU[int64, DeezerAlbumData]
+	pathParamsProvider func(*http.Request) map[string]string
+	newReleaseRules    *newrelease.Rules
+}
+
+func NewDeezerHandler(
+	newReleaseRules *newrelease.Rules,
+) *DeezerHandler {
+	return &DeezerHandler{
+		albumCache:         cache.NewLRU[int64, DeezerAlbumData](albumCacheSize),
+		pathParamsProvider: mux.Vars,
+		newReleaseRules:    newReleaseRules,
+	}
+}
@@ -0,0 +1,245 @@
+package discogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const apiUrlBase = "https://api.discogs.com"
+
+const (
+	trackType   = "track"
+	headingType = "heading"
+	indexType   = "index"
+)
+
+var releaseIdentifierRegex = regexp.MustCompile(`^(?:(release|master)s?/|([rm]))?(\d+)$`)
+var artistDisambiguationRegex = regexp.MustCompile(`\s+\(\d+\)$`)
+var discPositionRegex = regexp.MustCompile(`^(?i:cd)?(\d+)[-.]\d+$`)
+
+// Release pages may be localised ("/fr/release/...") or, in older links,
+// prefixed with the artist and title.
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPattern(constants.AlbumRequestType, `^https?://(?:www\.)?discogs\.com/(?:[^?#]*/)?(release|master)/(\d+)`),
+}
+
+func (discogsHandler *DiscogsHandler) Identifier() string {
+	return "discogs"
+}
+
+func (discogsHandler *DiscogsHandler) UrlPatterns() []handler.UrlPattern {
+	return urlPatterns
+}
+
+func (discogsHandler *DiscogsHandler) ValidateCredentials() error {
+	if discogsHandler.token == "" {
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	var data any
+	statusCode, err := discogsHandler.getJson(fmt.Sprintf("%s/oauth/identity", apiUrlBase), &data)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusOK {
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	return nil
+}
+
+// Album identifiers are a release ID ("123", "r123", "release/123") or a
+// master ID ("m456", "master/456"). Masters have no label or catalogue
+// number of their own, so they are read through their main release.
+func (discogsHandler *DiscogsHandler) Album(request *http.Request) (albumInfo handler.TrackCollectionInfo, err error) {
+	vars := discogsHandler.pathParamsProvider(request)
+	albumParamValue := vars[constants.AlbumIdentifierParam]
+	if discogsHandler.token == "" {
+		return handler.EmptyTrackCollectionInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	match := releaseIdentifierRegex.FindStringSubmatch(strings.Trim(albumParamValue, "/"))
+	if match == nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(albumParamValue)
+	}
+	releaseId := match[3]
+	if match[1] == "master" || match[2] == "m" {
+		var master DiscogsMasterData
+		statusCode, err := discogsHandler.getJson(fmt.Sprintf("%s/masters/%s", apiUrlBase, releaseId), &master)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		if err := collectionStatusError(statusCode, albumParamValue); err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		releaseId = fmt.Sprintf("%d", master.MainRelease)
+	}
+	var data DiscogsReleaseData
+	statusCode, err := discogsHandler.getJson(fmt.Sprintf("%s/releases/%s", apiUrlBase, releaseId), &data)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	if err := collectionStatusError(statusCode, albumParamValue); err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	releaseArtists := artistNames(data.Artists)
+	isSingle := releaseIsSingle(data)
+	releaseDate, releaseDatePrecision := releaseDateAndPrecision(data.Released, data.Year)
+	trackInfos := make([]handler.TrackInfo, 0)
+	for _, entry := range flattenTracklist(data.Tracklist) {
+		artists := releaseArtists
+		if len(entry.Artists) > 0 {
+			artists = artistNames(entry.Artists)
+		}
+		trackInfo := handler.NewTrackInfo(
+			artists,
+			entry.Title,
+			data.Title,
+			isSingle,
+			false,
+		)
+		trackInfo = discogsHandler.newReleaseRules.Apply(trackInfo, releaseDate, releaseDatePrecision)
+		trackInfo.DiscNumber = discNumber(entry.Position)
+		trackInfo.Duration = handler.SecondsFromClockDuration(entry.Duration)
+		trackInfos = append(trackInfos, trackInfo)
+	}
+	albumInfo = handler.NewTrackCollectionInfo(trackInfos, albumParamValue)
+	albumInfo.Release = &handler.ReleaseInfo{
+		Year: data.Year,
+	}
+	if len(data.Labels) > 0 {
+		albumInfo.Release.Label = artistDisambiguationRegex.ReplaceAllString(data.Labels[0].Name, "")
+		albumInfo.Release.CatalogueNumber = data.Labels[0].Catno
+	}
+	for i := range albumInfo.Tracks {
+		albumInfo.Tracks[i].Label = albumInfo.Release.Label
+	}
+	return albumInfo, nil
+}
+
+// Heading rows such as "Side A" are dropped, and index tracks (a titled
+// group of movements or a medley) are replaced by their sub tracks.
+func flattenTracklist(tracklist []DiscogsTrackData) []DiscogsTrackData {
+	tracks := make([]DiscogsTrackData, 0, len(tracklist))
+	for _, entry := range tracklist {
+		switch entry.Type {
+		case headingType:
+			continue
+		case indexType:
+			if len(entry.SubTracks) == 0 {
+				tracks = append(tracks, entry)
+				continue
+			}
+			for _, subTrack := range entry.SubTracks {
+				if len(subTrack.Artists) == 0 {
+					subTrack.Artists = entry.Artists
+				}
+				tracks = append(tracks, subTrack)
+			}
+		default:
+			tracks = append(tracks, entry)
+		}
+	}
+	return tracks
+}
+
+// Discogs suffixes duplicate artist names with a numeric disambiguator, e.g.
+// "Nirvana (2)", and uses the "anv" field when a release credits a variation.
+func artistNames(artists []DiscogsArtistData) string {
+	var builder strings.Builder
+	for i, artist := range artists {
+		name := artist.Anv
+		if name == "" {
+			name = artistDisambiguationRegex.ReplaceAllString(artist.Name, "")
+		}
+		builder.WriteString(name)
+		if i < len(artists)-1 {
+			join := strings.TrimSpace(artist.Join)
+			switch join {
+			case "", ",":
+				builder.WriteString(", ")
+			default:
+				builder.WriteString(fmt.Sprintf(" %s ", join))
+			}
+		}
+	}
+	return builder.String()
+}
+
+func releaseIsSingle(data DiscogsReleaseData) bool {
+	for _, format := range data.Formats {
+		if slices.Contains(format.Descriptions, "Single") {
+			return true
+		}
+	}
+	return false
+}
+
+// Multi-disc releases number tracks "{disc}-{track}", sometimes prefixed with
+// "CD". Vinyl sides ("A1", "B2") are not discs and are left unnumbered.
+func discNumber(position string) int {
+	matches := discPositionRegex.FindStringSubmatch(position)
+	if matches == nil {
+		return 0
+	}
+	disc, _ := strconv.Atoi(matches[1])
+	return disc
+}
+
+// Discogs release dates use "00" for an unknown month or day.
+func releaseDateAndPrecision(released string, year int) (string, string) {
+	parts := strings.Split(released, "-")
+	switch {
+	case len(parts) == 3 && parts[1] != "00" && parts[2] != "00":
+		return released, handler.ReleaseDatePrecisionDay
+	case len(parts) >= 2 && parts[1] != "00":
+		return fmt.Sprintf("%s-%s", parts[0], parts[1]), handler.ReleaseDatePrecisionMonth
+	case year > 0:
+		return fmt.Sprintf("%04d", year), handler.ReleaseDatePrecisionYear
+	default:
+		return "", ""
+	}
+}
+
+func collectionStatusError(statusCode int, collectionId string) error {
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusBadRequest:
+		return handler.NewInvalidTrackCollectionIdError(collectionId)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	case http.StatusNotFound:
+		return handler.NewTrackCollectionNotFoundError(collectionId)
+	default:
+		return fmt.Errorf("discogs API returned status: %d", statusCode)
+	}
+}
+
+func (discogsHandler *DiscogsHandler) getJson(url string, target any) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, handler.NewInternalError(err.Error())
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Discogs token=%s", discogsHandler.token))
+	req.Header.Add("User-Agent", constants.UserAgent)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, json.Unmarshal(body, target)
+}
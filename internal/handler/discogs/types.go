@@ -0,0 +1,61 @@
+package discogs
+
+import (
+	"net/http"
+
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type DiscogsArtistData struct {
+	Name string `json:"name"`
+	Anv  string `json:"anv"`
+	Join string `json:"join"`
+}
+
+type DiscogsTrackData struct {
+	Position  string              `json:"position"`
+	Type      string              `json:"type_"`
+	Title     string              `json:"title"`
+	Duration  string              `json:"duration"`
+	Artists   []DiscogsArtistData `json:"artists"`
+	SubTracks []DiscogsTrackData  `json:"sub_tracks"`
+}
+
+type DiscogsReleaseData struct {
+	Title    string              `json:"title"`
+	Year     int                 `json:"year"`
+	Released string              `json:"released"`
+	Artists  []DiscogsArtistData `json:"artists"`
+	Labels   []struct {
+		Name  string `json:"name"`
+		Catno string `json:"catno"`
+	} `json:"labels"`
+	Formats []struct {
+		Name         string   `json:"name"`
+		Descriptions []string `json:"descriptions"`
+	} `json:"formats"`
+	Tracklist []DiscogsTrackData `json:"tracklist"`
+}
+
+type DiscogsMasterData struct {
+	MainRelease int64 `json:"main_release"`
+}
+
+type DiscogsHandler struct {
+	token              string
+	pathParamsProvider func(*http.Request) map[string]string
+	newReleaseRules    *newrelease.Rules
+}
+
+func NewDiscogsHandler(
+	token string,
+	newReleaseRules *newrelease.Rules,
+) *DiscogsHandler {
+	return &DiscogsHandler{
+		token:              token,
+		pathParamsProvider: mux.Vars,
+		newReleaseRules:    newReleaseRules,
+	}
+}
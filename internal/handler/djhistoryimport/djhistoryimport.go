@@ -0,0 +1,72 @@
+package djhistoryimport
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/captaincoordinates/cick-playlister/internal/djhistory"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const (
+	uploadFileField           = "file"
+	minimumPlayedSecondsParam = "minimumPlayedSeconds"
+	playlistParam             = "playlist"
+	maximumUploadBytes        = 32 * 1024 * 1024
+)
+
+func (djHistoryImportHandler *DjHistoryImportHandler) Identifier() string {
+	return "djhistory"
+}
+
+func (djHistoryImportHandler *DjHistoryImportHandler) Upload(request *http.Request) (uploadInfo handler.TrackCollectionInfo, err error) {
+	if err := request.ParseMultipartForm(maximumUploadBytes); err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	defer request.MultipartForm.RemoveAll()
+	minimumPlayedSeconds := djHistoryImportHandler.defaultMinimumPlayedSeconds
+	if value := request.FormValue(minimumPlayedSecondsParam); value != "" {
+		minimumPlayedSeconds, err = strconv.ParseFloat(value, 64)
+		if err != nil || minimumPlayedSeconds < 0 {
+			return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(fmt.Sprintf("invalid %s '%s'", minimumPlayedSecondsParam, value))
+		}
+	}
+	file, fileHeader, err := request.FormFile(uploadFileField)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInternalError(err.Error())
+	}
+	entries, err := djhistory.Parse(fileHeader.Filename, content, djhistory.Options{
+		Playlist: request.FormValue(playlistParam),
+	})
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	entries = djhistory.FilterPlayed(entries, minimumPlayedSeconds)
+	trackInfos := make([]handler.TrackInfo, 0, len(entries))
+	for _, entry := range entries {
+		trackInfo := handler.NewTrackInfo(
+			entry.Artist,
+			entry.Title,
+			entry.Album,
+			false,
+			false,
+		)
+		if !entry.StartTime.IsZero() {
+			startTime := entry.StartTime
+			trackInfo.StartTime = &startTime
+		}
+		if entry.PlayedSeconds > 0 {
+			trackInfo.Duration = int(math.Round(entry.PlayedSeconds))
+		}
+		trackInfos = append(trackInfos, djHistoryImportHandler.newReleaseRules.Apply(trackInfo, "", ""))
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, fileHeader.Filename), nil
+}
@@ -0,0 +1,18 @@
+package djhistoryimport
+
+import "github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+type DjHistoryImportHandler struct {
+	defaultMinimumPlayedSeconds float64
+	newReleaseRules             *newrelease.Rules
+}
+
+func NewDjHistoryImportHandler(
+	defaultMinimumPlayedSeconds float64,
+	newReleaseRules *newrelease.Rules,
+) *DjHistoryImportHandler {
+	return &DjHistoryImportHandler{
+		defaultMinimumPlayedSeconds: defaultMinimumPlayedSeconds,
+		newReleaseRules:             newReleaseRules,
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	isoDurationRegex       = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	paddedIsoDurationRegex = regexp.MustCompile(`^P\d+H`)
+)
+
+func SecondsFromMilliseconds(milliseconds int64) int {
+	return int(math.Round(float64(milliseconds) / 1000))
+}
+
+// SecondsFromIsoDuration reads ISO 8601 durations such as "PT3M45S", as used
+// by YouTube, Tidal and schema.org metadata. Bandcamp pads its hours
+// ("P00H03M45S"), omitting the "T". Unreadable durations are 0.
+func SecondsFromIsoDuration(value string) int {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	// only the padded form is read as a time, as "P3M" is three months
+	if paddedIsoDurationRegex.MatchString(value) {
+		value = "PT" + value[1:]
+	}
+	matches := isoDurationRegex.FindStringSubmatch(value)
+	if matches == nil {
+		return 0
+	}
+	seconds := 0.0
+	for i, multiplier := range []float64{24 * 60 * 60, 60 * 60, 60, 1} {
+		if matches[i+1] != "" {
+			part, _ := strconv.ParseFloat(matches[i+1], 64)
+			seconds += part * multiplier
+		}
+	}
+	return int(math.Round(seconds))
+}
+
+// SecondsFromClockDuration reads "3:45" and "1:02:03" style durations.
+// Unreadable durations are 0.
+func SecondsFromClockDuration(value string) int {
+	seconds := 0
+	for _, part := range strings.Split(strings.TrimSpace(value), ":") {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return 0
+		}
+		seconds = seconds*60 + number
+	}
+	return seconds
+}
@@ -0,0 +1,50 @@
+package handler
+
+import "testing"
+
+func TestSecondsFromIsoDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"PT3M45S", 225},
+		{"PT1H2M3S", 3723},
+		{"PT45.6S", 46},
+		{"P1DT1S", 86401},
+		{"pt3m", 180},
+		{"P00H03M25S", 205},
+		{"P3M", 0},
+		{"P1D", 86400},
+		{"3:45", 0},
+		{"", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if got := SecondsFromIsoDuration(test.value); got != test.want {
+				t.Errorf("SecondsFromIsoDuration(%q): got %d, want %d", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSecondsFromClockDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"3:45", 225},
+		{"03:45", 225},
+		{"1:02:03", 3723},
+		{" 45 ", 45},
+		{"3:4x", 0},
+		{"-1:00", 0},
+		{"", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if got := SecondsFromClockDuration(test.value); got != test.want {
+				t.Errorf("SecondsFromClockDuration(%q): got %d, want %d", test.value, got, test.want)
+			}
+		})
+	}
+}
@@ -40,15 +40,27 @@ func NewHandlerAuthenticationError(credentialType HandlerCredentialType) Handler
 
 type TrackCollectionNotFoundError struct {
 	trackCollectionId string
+	reason            string
 }
 
 func (trackCollectionNotFoundError TrackCollectionNotFoundError) Error() string {
+	if trackCollectionNotFoundError.reason != "" {
+		return fmt.Sprintf("Track collection not found: %s (%s)", trackCollectionNotFoundError.trackCollectionId, trackCollectionNotFoundError.reason)
+	}
 	return fmt.Sprintf("Track collection not found: %s", trackCollectionNotFoundError.trackCollectionId)
 }
 
 func NewTrackCollectionNotFoundError(trackCollectionId string) TrackCollectionNotFoundError {
 	return TrackCollectionNotFoundError{
 		trackCollectionId,
+		"",
+	}
+}
+
+func NewTrackCollectionNotFoundErrorWithReason(trackCollectionId string, reason string) TrackCollectionNotFoundError {
+	return TrackCollectionNotFoundError{
+		trackCollectionId,
+		reason,
 	}
 }
 
@@ -93,3 +105,35 @@ func NewInvalidTrackIdError(trackId string) InvalidTrackIdError {
 		trackId,
 	}
 }
+
+type InvalidRequestError struct {
+	reason string
+}
+
+func (invalidRequestError InvalidRequestError) Error() string {
+	return fmt.Sprintf("Invalid request: %s", invalidRequestError.reason)
+}
+
+func NewInvalidRequestError(reason string) InvalidRequestError {
+	return InvalidRequestError{
+		reason,
+	}
+}
+
+// UpstreamError is a failure at the provider, such as an outage or a rate
+// limit, rather than a problem with the request.
+type UpstreamError struct {
+	provider string
+	reason   string
+}
+
+func (upstreamError UpstreamError) Error() string {
+	return fmt.Sprintf("Error at %s: %s", upstreamError.provider, upstreamError.reason)
+}
+
+func NewUpstreamError(provider string, reason string) UpstreamError {
+	return UpstreamError{
+		provider,
+		reason,
+	}
+}
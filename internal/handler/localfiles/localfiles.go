@@ -0,0 +1,241 @@
+package localfiles
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/tags"
+)
+
+const (
+	orderParam         = "order"
+	filenameOrder      = "filename"
+	modifiedOrder      = "modified"
+	uploadFilesField   = "files"
+	lastModifiedField  = "lastModified"
+	maximumUploadBytes = 32 * 1024 * 1024
+)
+
+var trackNumberPrefixRegex = regexp.MustCompile(`^\d{1,3}\s*[.\-_]\s*|^\d{1,3}\s+`)
+
+var errOutsideRoots = errors.New("path is outside the configured local files roots")
+
+var audioFileExtensions = []string{".mp3", ".flac", ".m4a", ".mp4", ".ogg", ".oga", ".opus"}
+
+func (localFilesHandler *LocalFilesHandler) Identifier() string {
+	return "localfiles"
+}
+
+// The playlist identifier is the path of a directory on the machine running
+// the server, such as a mounted USB stick. A leading separator is implied so
+// that absolute paths survive router path cleaning. Only directories within
+// a configured root can be read.
+func (localFilesHandler *LocalFilesHandler) Playlist(request *http.Request) (playlistInfo handler.TrackCollectionInfo, err error) {
+	vars := localFilesHandler.pathParamsProvider(request)
+	playlistParamValue := vars[constants.PlaylistIdentifierParam]
+	directory := filepath.FromSlash(playlistParamValue)
+	if !filepath.IsAbs(directory) {
+		directory = string(filepath.Separator) + directory
+	}
+	directory = resolvePath(directory)
+	if !localFilesHandler.isWithinRoots(directory) {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(playlistParamValue)
+	}
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return handler.EmptyTrackCollectionInfo, handler.NewTrackCollectionNotFoundError(playlistParamValue)
+		}
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(playlistParamValue)
+	}
+	files := make([]audioFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !isAudioFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, handler.NewInternalError(err.Error())
+		}
+		path := filepath.Join(directory, entry.Name())
+		files = append(files, audioFile{
+			name:     entry.Name(),
+			modified: info.ModTime(),
+			open: func() (io.ReadSeekCloser, error) {
+				return os.Open(path)
+			},
+		})
+	}
+	trackInfos, err := localFilesHandler.trackInfosFromAudioFiles(files, request.URL.Query().Get(orderParam))
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, playlistParamValue), nil
+}
+
+// Uploads are multipart forms with one "files" part per audio file. Browsers
+// do not send file modification times, so clients may send a "lastModified"
+// value (milliseconds since the epoch) per file, in the same order.
+func (localFilesHandler *LocalFilesHandler) Upload(request *http.Request) (uploadInfo handler.TrackCollectionInfo, err error) {
+	if err := request.ParseMultipartForm(maximumUploadBytes); err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	defer request.MultipartForm.RemoveAll()
+	fileHeaders := request.MultipartForm.File[uploadFilesField]
+	if len(fileHeaders) == 0 {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError("no files uploaded")
+	}
+	lastModifiedValues := request.MultipartForm.Value[lastModifiedField]
+	files := make([]audioFile, 0, len(fileHeaders))
+	for i, fileHeader := range fileHeaders {
+		var modified time.Time
+		if i < len(lastModifiedValues) {
+			if milliseconds, err := strconv.ParseInt(lastModifiedValues[i], 10, 64); err == nil {
+				modified = time.UnixMilli(milliseconds)
+			}
+		}
+		files = append(files, audioFile{
+			name:     fileHeader.Filename,
+			modified: modified,
+			open: func() (io.ReadSeekCloser, error) {
+				return fileHeader.Open()
+			},
+		})
+	}
+	trackInfos, err := localFilesHandler.trackInfosFromAudioFiles(files, request.URL.Query().Get(orderParam))
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, constants.RequestTypeNames[constants.UploadRequestType]), nil
+}
+
+func (localFilesHandler *LocalFilesHandler) trackInfosFromAudioFiles(files []audioFile, order string) ([]handler.TrackInfo, error) {
+	switch order {
+	case "", filenameOrder:
+		sort.SliceStable(files, func(i, j int) bool {
+			return strings.ToLower(files[i].name) < strings.ToLower(files[j].name)
+		})
+	case modifiedOrder:
+		sort.SliceStable(files, func(i, j int) bool {
+			return files[i].modified.Before(files[j].modified)
+		})
+	default:
+		return nil, handler.NewInvalidRequestError(fmt.Sprintf("unsupported order '%s'", order))
+	}
+	trackInfos := make([]handler.TrackInfo, 0, len(files))
+	for _, file := range files {
+		trackInfo, err := localFilesHandler.trackInfoFromAudioFile(file)
+		if err != nil {
+			return nil, err
+		}
+		trackInfos = append(trackInfos, trackInfo)
+	}
+	return trackInfos, nil
+}
+
+func (localFilesHandler *LocalFilesHandler) trackInfoFromAudioFile(file audioFile) (handler.TrackInfo, error) {
+	reader, err := file.open()
+	if err != nil {
+		return handler.EmptyTrackInfo, handler.NewInternalError(err.Error())
+	}
+	defer reader.Close()
+	fileTags, err := tags.Read(reader)
+	if err != nil || fileTags.IsEmpty() {
+		trackInfo := trackInfoFromFilename(file.name)
+		trackInfo.Duration = fileTags.Duration
+		return localFilesHandler.newReleaseRules.Apply(trackInfo, "", ""), nil
+	}
+	artist := fileTags.Artist
+	if artist == "" {
+		artist = fileTags.AlbumArtist
+	}
+	trackInfo := handler.NewTrackInfo(
+		artist,
+		fileTags.Title,
+		fileTags.Album,
+		fileTags.TrackTotal == 1,
+		false,
+	)
+	trackInfo = localFilesHandler.newReleaseRules.Apply(trackInfo, fileTags.ReleaseDate(), handler.ReleaseDatePrecision(fileTags.ReleaseDate()))
+	trackInfo.DiscNumber = fileTags.DiscNumber
+	trackInfo.Duration = fileTags.Duration
+	return trackInfo, nil
+}
+
+// TrackInfoFromPath reads a single audio file's tags, for other handlers
+// that encounter references to local files. Like directories, files outside
+// the configured roots are not read.
+func (localFilesHandler *LocalFilesHandler) TrackInfoFromPath(path string) (handler.TrackInfo, error) {
+	path = resolvePath(path)
+	if !localFilesHandler.isWithinRoots(path) {
+		return handler.EmptyTrackInfo, errOutsideRoots
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	return localFilesHandler.trackInfoFromAudioFile(audioFile{
+		name:     filepath.Base(path),
+		modified: info.ModTime(),
+		open: func() (io.ReadSeekCloser, error) {
+			return os.Open(path)
+		},
+	})
+}
+
+// Untagged files are often named "Artist - Title", optionally prefixed with
+// a track number; anything inferred from a filename is low confidence.
+func trackInfoFromFilename(filename string) handler.TrackInfo {
+	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+	name = trackNumberPrefixRegex.ReplaceAllString(name, "")
+	artist, track, found := strings.Cut(name, " - ")
+	if !found {
+		artist, track = "", name
+	}
+	trackInfo := handler.NewTrackInfo(
+		strings.TrimSpace(artist),
+		strings.TrimSpace(track),
+		"",
+		false,
+		false,
+	)
+	trackInfo.LowConfidence = true
+	return trackInfo
+}
+
+// Symbolic links are resolved so that a link inside a root cannot lead
+// outside it. Paths that do not exist are only cleaned.
+func resolvePath(path string) string {
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+func (localFilesHandler *LocalFilesHandler) isWithinRoots(path string) bool {
+	for _, root := range localFilesHandler.roots {
+		relative, err := filepath.Rel(resolvePath(root), path)
+		if err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAudioFile(filename string) bool {
+	return slices.Contains(audioFileExtensions, strings.ToLower(filepath.Ext(filename)))
+}
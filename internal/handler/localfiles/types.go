@@ -0,0 +1,34 @@
+package localfiles
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type audioFile struct {
+	name     string
+	modified time.Time
+	open     func() (io.ReadSeekCloser, error)
+}
+
+type LocalFilesHandler struct {
+	pathParamsProvider func(*http.Request) map[string]string
+	roots              []string
+	newReleaseRules    *newrelease.Rules
+}
+
+func NewLocalFilesHandler(
+	roots []string,
+	newReleaseRules *newrelease.Rules,
+) *LocalFilesHandler {
+	return &LocalFilesHandler{
+		pathParamsProvider: mux.Vars,
+		roots:              roots,
+		newReleaseRules:    newReleaseRules,
+	}
+}
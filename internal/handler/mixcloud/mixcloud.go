@@ -0,0 +1,119 @@
+package mixcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const apiUrlBase = "https://api.mixcloud.com"
+const trackSectionType = "track"
+
+// Cloudcasts share the "{user}/{slug}" shape with profile tabs and Mixcloud's
+// own pages, which are excluded.
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPatternExcluding(
+		constants.PlaylistRequestType,
+		`^https?://(?:www\.|m\.)?mixcloud\.com/([^/?#]+)/([^/?#]+)/?(?:[?#]|$)`,
+		"uploads", "favorites", "listens", "playlists", "reposts", "stream", "shows",
+		"followers", "following", "activity", "stats",
+		"discover", "upload", "dashboard", "settings", "search", "live", "select", "categories",
+		"about", "developers", "tag",
+	),
+}
+
+func (mixcloudHandler *MixcloudHandler) Identifier() string {
+	return "mixcloud"
+}
+
+func (mixcloudHandler *MixcloudHandler) UrlPatterns() []handler.UrlPattern {
+	return urlPatterns
+}
+
+// Cloudcasts are identified by the "{user}/{slug}" path of their URL. The
+// tracklist is exposed as "sections", which may also contain chapter markers
+// that are not tracks.
+func (mixcloudHandler *MixcloudHandler) Playlist(request *http.Request) (playlistInfo handler.TrackCollectionInfo, err error) {
+	vars := mixcloudHandler.pathParamsProvider(request)
+	playlistParamValue := vars[constants.PlaylistIdentifierParam]
+	cloudcastKey := strings.Trim(playlistParamValue, "/")
+	if len(strings.Split(cloudcastKey, "/")) != 2 {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(playlistParamValue)
+	}
+	url := fmt.Sprintf(
+		"%s/%s/",
+		apiUrlBase,
+		cloudcastKey,
+	)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInternalError(err.Error())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(playlistParamValue)
+		case http.StatusNotFound:
+			return handler.EmptyTrackCollectionInfo, handler.NewTrackCollectionNotFoundError(playlistParamValue)
+		default:
+			return handler.EmptyTrackCollectionInfo, fmt.Errorf("mixcloud API returned status: %d", resp.StatusCode)
+		}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	var data MixcloudCloudcastData
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	sort.SliceStable(data.Sections, func(i, j int) bool {
+		return data.Sections[i].StartTime < data.Sections[j].StartTime
+	})
+	trackInfos := make([]handler.TrackInfo, 0)
+	for i, section := range data.Sections {
+		if section.SectionType != trackSectionType || section.Track == nil {
+			continue
+		}
+		trackInfo := handler.NewTrackInfo(
+			section.Track.Artist.Name,
+			section.Track.Name,
+			"",
+			false,
+			false,
+		)
+		trackInfo.Duration = sectionDuration(data, i)
+		trackInfos = append(trackInfos, mixcloudHandler.newReleaseRules.Apply(trackInfo, "", ""))
+	}
+	if len(trackInfos) == 0 {
+		return handler.EmptyTrackCollectionInfo, handler.NewTrackCollectionNotFoundErrorWithReason(playlistParamValue, "cloudcast has no tracklist")
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, playlistParamValue), nil
+}
+
+// Sections only carry a start time, so a track runs until the next section
+// starts, or until the end of the cloudcast. Untimed tracklists start every
+// section at 0, which leaves durations unknown.
+func sectionDuration(data MixcloudCloudcastData, index int) int {
+	last := len(data.Sections) - 1
+	if last > 0 && data.Sections[last].StartTime == 0 {
+		return 0
+	}
+	end := data.AudioLength
+	if index < last {
+		end = data.Sections[index+1].StartTime
+	}
+	return max(end-data.Sections[index].StartTime, 0)
+}
@@ -0,0 +1,38 @@
+package mixcloud
+
+import (
+	"net/http"
+
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type MixcloudCloudcastData struct {
+	Name        string `json:"name"`
+	AudioLength int    `json:"audio_length"`
+	Sections    []struct {
+		StartTime   int    `json:"start_time"`
+		SectionType string `json:"section_type"`
+		Track       *struct {
+			Name   string `json:"name"`
+			Artist struct {
+				Name string `json:"name"`
+			} `json:"artist"`
+		} `json:"track"`
+	} `json:"sections"`
+}
+
+type MixcloudHandler struct {
+	pathParamsProvider func(*http.Request) map[string]string
+	newReleaseRules    *newrelease.Rules
+}
+
+func NewMixcloudHandler(
+	newReleaseRules *newrelease.Rules,
+) *MixcloudHandler {
+	return &MixcloudHandler{
+		pathParamsProvider: mux.Vars,
+		newReleaseRules:    newReleaseRules,
+	}
+}
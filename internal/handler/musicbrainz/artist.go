@@ -0,0 +1,50 @@
+package musicbrainz
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/cancon"
+)
+
+const minimumArtistSearchScore = 90
+
+// ArtistOrigin looks up where an artist is from, for callers that only have
+// an artist's name. Only a confident match with the same name is accepted,
+// as a wrong artist is worse than none.
+func (musicBrainzHandler *MusicBrainzHandler) ArtistOrigin(name string) (cancon.ArtistOrigin, bool, error) {
+	phrase := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name)
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf(`artist:"%s"`, phrase))
+	query.Set("limit", "5")
+	query.Set("fmt", "json")
+	var data MusicBrainzArtistSearchData
+	statusCode, err := musicBrainzHandler.getJson(fmt.Sprintf("%s/artist?%s", apiUrlBase, query.Encode()), &data)
+	if err != nil {
+		return cancon.ArtistOrigin{}, false, err
+	}
+	if statusCode != http.StatusOK {
+		return cancon.ArtistOrigin{}, false, fmt.Errorf("musicbrainz API returned status: %d", statusCode)
+	}
+	for _, artist := range data.Artists {
+		if artist.Score < minimumArtistSearchScore || !strings.EqualFold(strings.TrimSpace(artist.Name), strings.TrimSpace(name)) {
+			continue
+		}
+		origin := cancon.ArtistOrigin{
+			Name:    artist.Name,
+			Country: artist.Country,
+		}
+		for _, area := range []*MusicBrainzAreaData{artist.Area, artist.BeginArea} {
+			if area == nil || area.Name == "" {
+				continue
+			}
+			origin.Areas = append(origin.Areas, area.Name)
+			origin.AreaCodes = append(origin.AreaCodes, area.Iso31661Codes...)
+			origin.AreaCodes = append(origin.AreaCodes, area.Iso31662Codes...)
+		}
+		return origin, true, nil
+	}
+	return cancon.ArtistOrigin{}, false, nil
+}
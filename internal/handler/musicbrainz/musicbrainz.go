@@ -0,0 +1,242 @@
+package musicbrainz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const apiUrlBase = "https://musicbrainz.org/ws/2"
+const minimumRequestInterval = time.Second
+const singlePrimaryType = "Single"
+const performanceRelationType = "performance"
+const instrumentalAttribute = "instrumental"
+const noLinguisticContentLanguage = "zxx"
+
+var instrumentalRegex = regexp.MustCompile(`(?i)\binstrumental\b`)
+
+var mbidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPattern(constants.AlbumRequestType, `^https?://(?:beta\.)?musicbrainz\.org/release/([0-9a-f-]{36})`),
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://(?:beta\.)?musicbrainz\.org/recording/([0-9a-f-]{36})`),
+}
+
+func (musicBrainzHandler *MusicBrainzHandler) Identifier() string {
+	return "musicbrainz"
+}
+
+func (musicBrainzHandler *MusicBrainzHandler) UrlPatterns() []handler.UrlPattern {
+	return urlPatterns
+}
+
+func (musicBrainzHandler *MusicBrainzHandler) Track(request *http.Request) (trackInfo handler.TrackInfo, err error) {
+	vars := musicBrainzHandler.pathParamsProvider(request)
+	trackParamValue := vars[constants.TrackIdentifierParam]
+	if !mbidRegex.MatchString(trackParamValue) {
+		return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(trackParamValue)
+	}
+	url := fmt.Sprintf(
+		"%s/recording/%s?inc=artist-credits+releases+release-groups+work-rels&fmt=json",
+		apiUrlBase,
+		trackParamValue,
+	)
+	var data MusicBrainzRecordingData
+	statusCode, err := musicBrainzHandler.getJson(url, &data)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(trackParamValue)
+	case http.StatusNotFound:
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	default:
+		return handler.EmptyTrackInfo, fmt.Errorf("musicbrainz API returned status: %d", statusCode)
+	}
+	release := earliestRelease(data.Releases)
+	releaseDate := data.FirstReleaseDate
+	if releaseDate == "" {
+		releaseDate = release.Date
+	}
+	trackInfo = handler.NewTrackInfo(
+		artistCreditString(data.ArtistCredit),
+		data.Title,
+		release.Title,
+		release.ReleaseGroup.PrimaryType == singlePrimaryType,
+		false,
+	)
+	trackInfo = musicBrainzHandler.newReleaseRules.Apply(trackInfo, releaseDate, handler.ReleaseDatePrecision(releaseDate))
+	trackInfo.Duration = handler.SecondsFromMilliseconds(data.Length)
+	trackInfo.IsInstrumental = recordingIsInstrumental(data.Disambiguation, data.Relations)
+	return trackInfo, nil
+}
+
+func (musicBrainzHandler *MusicBrainzHandler) Album(request *http.Request) (albumInfo handler.TrackCollectionInfo, err error) {
+	vars := musicBrainzHandler.pathParamsProvider(request)
+	albumParamValue := vars[constants.AlbumIdentifierParam]
+	if !mbidRegex.MatchString(albumParamValue) {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(albumParamValue)
+	}
+	url := fmt.Sprintf(
+		"%s/release/%s?inc=recordings+artist-credits+release-groups+labels+recording-level-rels+work-rels&fmt=json",
+		apiUrlBase,
+		albumParamValue,
+	)
+	var data MusicBrainzReleaseData
+	statusCode, err := musicBrainzHandler.getJson(url, &data)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(albumParamValue)
+	case http.StatusNotFound:
+		return handler.EmptyTrackCollectionInfo, handler.NewTrackCollectionNotFoundError(albumParamValue)
+	default:
+		return handler.EmptyTrackCollectionInfo, fmt.Errorf("musicbrainz API returned status: %d", statusCode)
+	}
+	releaseDate := data.ReleaseGroup.FirstReleaseDate
+	if releaseDate == "" {
+		releaseDate = data.Date
+	}
+	isSingle := data.ReleaseGroup.PrimaryType == singlePrimaryType
+	trackInfos := make([]handler.TrackInfo, 0)
+	for _, medium := range data.Media {
+		for _, entry := range medium.Tracks {
+			trackInfo := handler.NewTrackInfo(
+				artistCreditString(entry.ArtistCredit),
+				entry.Title,
+				data.Title,
+				isSingle,
+				false,
+			)
+			trackInfo = musicBrainzHandler.newReleaseRules.Apply(trackInfo, releaseDate, handler.ReleaseDatePrecision(releaseDate))
+			trackInfo.DiscNumber = medium.Position
+			trackInfo.Duration = handler.SecondsFromMilliseconds(entry.Length)
+			trackInfo.IsInstrumental = recordingIsInstrumental(entry.Recording.Disambiguation, entry.Recording.Relations)
+			trackInfos = append(trackInfos, trackInfo)
+		}
+	}
+	albumInfo = handler.NewTrackCollectionInfo(trackInfos, albumParamValue)
+	albumInfo.Release = &handler.ReleaseInfo{}
+	if len(data.Date) >= len("2006") {
+		fmt.Sscanf(data.Date[:4], "%d", &albumInfo.Release.Year)
+	}
+	for _, labelInfo := range data.LabelInfo {
+		if labelInfo.Label != nil {
+			albumInfo.Release.Label = labelInfo.Label.Name
+			albumInfo.Release.CatalogueNumber = labelInfo.CatalogNumber
+			break
+		}
+	}
+	for i := range albumInfo.Tracks {
+		albumInfo.Tracks[i].Label = albumInfo.Release.Label
+	}
+	return albumInfo, nil
+}
+
+// A recording is instrumental if it is described as one, if it is marked as
+// an instrumental performance of a work, or if the work it performs has no
+// lyrics. A recording that performs a work with lyrics, without being marked
+// instrumental, is not. Without any of these the recording says nothing
+// either way.
+func recordingIsInstrumental(disambiguation string, relations MusicBrainzWorkRelationsData) string {
+	if instrumentalRegex.MatchString(disambiguation) {
+		return handler.AssessmentTrue
+	}
+	hasLyrics := false
+	hasWithoutLyrics := false
+	for _, relation := range relations {
+		if relation.Type != performanceRelationType || relation.Work == nil {
+			continue
+		}
+		if slices.Contains(relation.Attributes, instrumentalAttribute) {
+			return handler.AssessmentTrue
+		}
+		languages := relation.Work.Languages
+		if len(languages) == 0 && relation.Work.Language != "" {
+			languages = []string{relation.Work.Language}
+		}
+		for _, language := range languages {
+			if language == noLinguisticContentLanguage {
+				hasWithoutLyrics = true
+			} else {
+				hasLyrics = true
+			}
+		}
+	}
+	switch {
+	case hasLyrics:
+		return handler.AssessmentFalse
+	case hasWithoutLyrics:
+		return handler.AssessmentTrue
+	default:
+		return ""
+	}
+}
+
+// Recordings appear on many releases (compilations, reissues); the earliest
+// dated one is the best candidate for the album the recording came from.
+func earliestRelease(releases []MusicBrainzReleaseData) MusicBrainzReleaseData {
+	var earliest MusicBrainzReleaseData
+	for _, release := range releases {
+		if earliest.Title == "" || (release.Date != "" && (earliest.Date == "" || release.Date < earliest.Date)) {
+			earliest = release
+		}
+	}
+	return earliest
+}
+
+func artistCreditString(artistCredit MusicBrainzArtistCreditData) string {
+	var builder strings.Builder
+	for _, credit := range artistCredit {
+		builder.WriteString(credit.Name)
+		builder.WriteString(credit.JoinPhrase)
+	}
+	return builder.String()
+}
+
+// MusicBrainz allows one request per second per client and blocks clients
+// that exceed it or that do not identify themselves with a User-Agent.
+func (musicBrainzHandler *MusicBrainzHandler) waitForRateLimit() {
+	musicBrainzHandler.rateLimitMutex.Lock()
+	defer musicBrainzHandler.rateLimitMutex.Unlock()
+	if wait := minimumRequestInterval - time.Since(musicBrainzHandler.lastRequestTime); wait > 0 {
+		time.Sleep(wait)
+	}
+	musicBrainzHandler.lastRequestTime = time.Now()
+}
+
+func (musicBrainzHandler *MusicBrainzHandler) getJson(url string, target any) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, handler.NewInternalError(err.Error())
+	}
+	req.Header.Add("User-Agent", constants.UserAgent)
+	req.Header.Add("Accept", "application/json")
+	musicBrainzHandler.waitForRateLimit()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, json.Unmarshal(body, target)
+}
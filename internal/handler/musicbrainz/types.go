@@ -0,0 +1,99 @@
+package musicbrainz
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type MusicBrainzArtistCreditData []struct {
+	Name       string `json:"name"`
+	JoinPhrase string `json:"joinphrase"`
+}
+
+// Performance relations link a recording to the work it performs; works
+// without lyrics have the language "zxx".
+type MusicBrainzWorkRelationsData []struct {
+	Type       string   `json:"type"`
+	Attributes []string `json:"attributes"`
+	Work       *struct {
+		Language  string   `json:"language"`
+		Languages []string `json:"languages"`
+	} `json:"work"`
+}
+
+type MusicBrainzReleaseGroupData struct {
+	PrimaryType      string `json:"primary-type"`
+	FirstReleaseDate string `json:"first-release-date"`
+}
+
+type MusicBrainzReleaseData struct {
+	Title        string                      `json:"title"`
+	Date         string                      `json:"date"`
+	ArtistCredit MusicBrainzArtistCreditData `json:"artist-credit"`
+	ReleaseGroup MusicBrainzReleaseGroupData `json:"release-group"`
+	LabelInfo    []struct {
+		CatalogNumber string `json:"catalog-number"`
+		Label         *struct {
+			Name string `json:"name"`
+		} `json:"label"`
+	} `json:"label-info"`
+	Media []struct {
+		Position int `json:"position"`
+		Tracks   []struct {
+			Title        string                      `json:"title"`
+			Length       int64                       `json:"length"`
+			ArtistCredit MusicBrainzArtistCreditData `json:"artist-credit"`
+			Recording    struct {
+				Disambiguation string                       `json:"disambiguation"`
+				Relations      MusicBrainzWorkRelationsData `json:"relations"`
+			} `json:"recording"`
+		} `json:"tracks"`
+	} `json:"media"`
+}
+
+type MusicBrainzRecordingData struct {
+	Title            string                       `json:"title"`
+	Length           int64                        `json:"length"`
+	Disambiguation   string                       `json:"disambiguation"`
+	Relations        MusicBrainzWorkRelationsData `json:"relations"`
+	FirstReleaseDate string                       `json:"first-release-date"`
+	ArtistCredit     MusicBrainzArtistCreditData  `json:"artist-credit"`
+	Releases         []MusicBrainzReleaseData     `json:"releases"`
+}
+
+type MusicBrainzAreaData struct {
+	Name          string   `json:"name"`
+	Iso31661Codes []string `json:"iso-3166-1-codes"`
+	Iso31662Codes []string `json:"iso-3166-2-codes"`
+}
+
+type MusicBrainzArtistSearchData struct {
+	Artists []struct {
+		Name      string               `json:"name"`
+		Score     int                  `json:"score"`
+		Country   string               `json:"country"`
+		Area      *MusicBrainzAreaData `json:"area"`
+		BeginArea *MusicBrainzAreaData `json:"begin-area"`
+	} `json:"artists"`
+}
+
+type MusicBrainzHandler struct {
+	lastRequestTime    time.Time
+	rateLimitMutex     sync.Mutex
+	pathParamsProvider func(*http.Request) map[string]string
+	newReleaseRules    *newrelease.Rules
+}
+
+func NewMusicBrainzHandler(
+	newReleaseRules *newrelease.Rules,
+) *MusicBrainzHandler {
+	return &MusicBrainzHandler{
+		pathParamsProvider: mux.Vars,
+		newReleaseRules:    newReleaseRules,
+	}
+}
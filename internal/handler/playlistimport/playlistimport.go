@@ -0,0 +1,124 @@
+package playlistimport
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/playlistfile"
+)
+
+const (
+	uploadFileField    = "file"
+	enrichParam        = "enrich"
+	baseDirectoryParam = "baseDirectory"
+	maximumUploadBytes = 8 * 1024 * 1024
+)
+
+func (playlistImportHandler *PlaylistImportHandler) Identifier() string {
+	return "playlistfile"
+}
+
+// Upload accepts a single M3U, M3U8, PLS or XSPF file as the "file" part of a
+// multipart form. With enrich=true, entries that point at local audio files
+// are read for tags, resolving relative locations against baseDirectory.
+func (playlistImportHandler *PlaylistImportHandler) Upload(request *http.Request) (uploadInfo handler.TrackCollectionInfo, err error) {
+	if err := request.ParseMultipartForm(maximumUploadBytes); err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	defer request.MultipartForm.RemoveAll()
+	file, fileHeader, err := request.FormFile(uploadFileField)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInternalError(err.Error())
+	}
+	entries, err := playlistfile.Parse(fileHeader.Filename, content)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	enrich := request.FormValue(enrichParam) == "true"
+	baseDirectory := request.FormValue(baseDirectoryParam)
+	trackInfos := make([]handler.TrackInfo, 0, len(entries))
+	for _, entry := range entries {
+		if enrich {
+			if filePath, ok := localPath(entry.Location, baseDirectory); ok {
+				if trackInfo, err := playlistImportHandler.localFileReader(filePath); err == nil && !trackInfo.LowConfidence {
+					if trackInfo.Duration == 0 {
+						trackInfo.Duration = entry.DurationSeconds
+					}
+					trackInfos = append(trackInfos, trackInfo)
+					continue
+				}
+			}
+		}
+		trackInfos = append(trackInfos, playlistImportHandler.newReleaseRules.Apply(trackInfoFromEntry(entry), "", ""))
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, fileHeader.Filename), nil
+}
+
+// Entries without any metadata are named after their location, which is
+// unreliable and therefore flagged as low confidence.
+func trackInfoFromEntry(entry playlistfile.Entry) handler.TrackInfo {
+	if entry.Title != "" {
+		trackInfo := handler.NewTrackInfo(
+			entry.Artist,
+			entry.Title,
+			entry.Album,
+			false,
+			false,
+		)
+		trackInfo.Duration = entry.DurationSeconds
+		return trackInfo
+	}
+	name := entry.Location
+	if parsed, err := url.Parse(entry.Location); err == nil && parsed.Scheme != "" && len(parsed.Scheme) > 1 {
+		name = parsed.Path
+	}
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSuffix(name, path.Ext(name))
+	artist, title, _ := playlistfile.SplitArtistTitle(name)
+	trackInfo := handler.NewTrackInfo(
+		artist,
+		title,
+		entry.Album,
+		false,
+		false,
+	)
+	trackInfo.LowConfidence = true
+	trackInfo.Duration = entry.DurationSeconds
+	return trackInfo
+}
+
+// Locations may be plain paths, relative paths or file:// URLs; other URL
+// schemes (streams, web links) are not local files. Single letter schemes
+// are Windows drive letters.
+func localPath(location string, baseDirectory string) (string, bool) {
+	if location == "" {
+		return "", false
+	}
+	if parsed, err := url.Parse(location); err == nil && len(parsed.Scheme) > 1 {
+		if parsed.Scheme != "file" {
+			return "", false
+		}
+		location = strings.TrimPrefix(parsed.Path, "/")
+		if !strings.Contains(location, ":") {
+			location = "/" + location
+		}
+	}
+	localPath := filepath.FromSlash(location)
+	if !filepath.IsAbs(localPath) && !strings.Contains(localPath, ":") {
+		if baseDirectory == "" {
+			return "", false
+		}
+		localPath = filepath.Join(baseDirectory, localPath)
+	}
+	return localPath, true
+}
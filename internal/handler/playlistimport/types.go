@@ -0,0 +1,21 @@
+package playlistimport
+
+import (
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+)
+
+type PlaylistImportHandler struct {
+	localFileReader func(string) (handler.TrackInfo, error)
+	newReleaseRules *newrelease.Rules
+}
+
+func NewPlaylistImportHandler(
+	localFileReader func(string) (handler.TrackInfo, error),
+	newReleaseRules *newrelease.Rules,
+) *PlaylistImportHandler {
+	return &PlaylistImportHandler{
+		localFileReader: localFileReader,
+		newReleaseRules: newReleaseRules,
+	}
+}
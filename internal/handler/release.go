@@ -0,0 +1,18 @@
+package handler
+
+const (
+	ReleaseDatePrecisionDay   = "day"
+	ReleaseDatePrecisionMonth = "month"
+	ReleaseDatePrecisionYear  = "year"
+)
+
+func ReleaseDatePrecision(releaseDate string) string {
+	switch len(releaseDate) {
+	case len("2006"):
+		return ReleaseDatePrecisionYear
+	case len("2006-01"):
+		return ReleaseDatePrecisionMonth
+	default:
+		return ReleaseDatePrecisionDay
+	}
+}
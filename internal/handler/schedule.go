@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	ScheduleShowStartParam   = "showStart"
+	ScheduleSlotMinutesParam = "slotMinutes"
+	clockTimeLayout          = "15:04"
+)
+
+var ScheduleParams = []string{ScheduleShowStartParam, ScheduleSlotMinutesParam}
+
+// TrackSchedule estimates when each track in a collection will air, assuming
+// the tracks play back to back from the start of the show. Without a slot
+// length only the start times are estimated.
+type TrackSchedule struct {
+	showStart  *time.Time
+	slotLength time.Duration
+}
+
+func (trackSchedule TrackSchedule) IsEmpty() bool {
+	return trackSchedule.showStart == nil
+}
+
+// The show start is an RFC 3339 time, or a time of day ("14:00") for a show
+// airing today in the server's time zone.
+func NewTrackSchedule(query url.Values) (TrackSchedule, error) {
+	var trackSchedule TrackSchedule
+	if value := query.Get(ScheduleShowStartParam); value != "" {
+		showStart, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			clockTime, clockErr := time.ParseInLocation(clockTimeLayout, value, time.Local)
+			if clockErr != nil {
+				return trackSchedule, NewInvalidRequestError(fmt.Sprintf("invalid '%s': %s", ScheduleShowStartParam, value))
+			}
+			now := time.Now()
+			showStart = time.Date(now.Year(), now.Month(), now.Day(), clockTime.Hour(), clockTime.Minute(), 0, 0, time.Local)
+		}
+		trackSchedule.showStart = &showStart
+	}
+	slotMinutes, err := PositiveIntParam(query, ScheduleSlotMinutesParam)
+	if err != nil {
+		return trackSchedule, err
+	}
+	if slotMinutes > 0 {
+		if trackSchedule.showStart == nil {
+			return trackSchedule, NewInvalidRequestError(fmt.Sprintf("'%s' requires '%s'", ScheduleSlotMinutesParam, ScheduleShowStartParam))
+		}
+		trackSchedule.slotLength = time.Duration(slotMinutes) * time.Minute
+	}
+	return trackSchedule, nil
+}
+
+// Apply sets each track's estimated start time and, with a slot length, flags
+// the tracks that would still be playing when the slot ends. Tracks without a
+// duration are counted so that an incomplete estimate can be recognised;
+// inserted rows without one are taken to be short.
+func (trackSchedule TrackSchedule) Apply(collectionInfo TrackCollectionInfo) TrackCollectionInfo {
+	if trackSchedule.IsEmpty() {
+		return collectionInfo
+	}
+	slotEnd := trackSchedule.showStart.Add(trackSchedule.slotLength)
+	scheduleInfo := ScheduleInfo{
+		ShowStart:  *trackSchedule.showStart,
+		SlotLength: int(trackSchedule.slotLength.Seconds()),
+	}
+	tracks := make([]TrackInfo, len(collectionInfo.Tracks))
+	startTime := *trackSchedule.showStart
+	for i, track := range collectionInfo.Tracks {
+		estimatedStartTime := startTime
+		track.EstimatedStartTime = &estimatedStartTime
+		if track.Duration == 0 && track.RowType == "" {
+			scheduleInfo.UnknownDurations++
+		}
+		startTime = startTime.Add(time.Duration(track.Duration) * time.Second)
+		track.Overruns = trackSchedule.slotLength > 0 && startTime.After(slotEnd)
+		tracks[i] = track
+	}
+	collectionInfo.Tracks = tracks
+	scheduleInfo.Runtime = int(startTime.Sub(*trackSchedule.showStart).Seconds())
+	if trackSchedule.slotLength > 0 {
+		scheduleInfo.Remaining = scheduleInfo.SlotLength - scheduleInfo.Runtime
+		scheduleInfo.Overruns = scheduleInfo.Remaining < 0
+	}
+	collectionInfo.Schedule = &scheduleInfo
+	return collectionInfo
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrackScheduleApply(t *testing.T) {
+	showStart := time.Date(2024, time.March, 8, 14, 0, 0, 0, time.UTC)
+	at := func(minutes int, seconds int) *time.Time {
+		startTime := showStart.Add(time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
+		return &startTime
+	}
+	tracks := []TrackInfo{
+		{Track: "T1", Duration: 240},
+		{Track: "T2"},
+		{Track: "Station ID", RowType: RowTypeStationId},
+		{Track: "T3", Duration: 240},
+		{Track: "T4", Duration: 180},
+	}
+	tests := []struct {
+		name         string
+		query        url.Values
+		wantTracks   []TrackInfo
+		wantSchedule *ScheduleInfo
+	}{
+		{
+			name:         "no schedule",
+			query:        url.Values{},
+			wantTracks:   tracks,
+			wantSchedule: nil,
+		},
+		{
+			name:  "show start",
+			query: url.Values{ScheduleShowStartParam: {"2024-03-08T14:00:00Z"}},
+			wantTracks: []TrackInfo{
+				{Track: "T1", Duration: 240, EstimatedStartTime: at(0, 0)},
+				{Track: "T2", EstimatedStartTime: at(4, 0)},
+				{Track: "Station ID", RowType: RowTypeStationId, EstimatedStartTime: at(4, 0)},
+				{Track: "T3", Duration: 240, EstimatedStartTime: at(4, 0)},
+				{Track: "T4", Duration: 180, EstimatedStartTime: at(8, 0)},
+			},
+			wantSchedule: &ScheduleInfo{ShowStart: showStart, Runtime: 660, UnknownDurations: 1},
+		},
+		{
+			name: "slot overrun",
+			query: url.Values{
+				ScheduleShowStartParam:   {"2024-03-08T14:00:00Z"},
+				ScheduleSlotMinutesParam: {"10"},
+			},
+			wantTracks: []TrackInfo{
+				{Track: "T1", Duration: 240, EstimatedStartTime: at(0, 0)},
+				{Track: "T2", EstimatedStartTime: at(4, 0)},
+				{Track: "Station ID", RowType: RowTypeStationId, EstimatedStartTime: at(4, 0)},
+				{Track: "T3", Duration: 240, EstimatedStartTime: at(4, 0)},
+				{Track: "T4", Duration: 180, EstimatedStartTime: at(8, 0), Overruns: true},
+			},
+			wantSchedule: &ScheduleInfo{ShowStart: showStart, SlotLength: 600, Runtime: 660, Remaining: -60, Overruns: true, UnknownDurations: 1},
+		},
+		{
+			name: "slot with time to spare",
+			query: url.Values{
+				ScheduleShowStartParam:   {"2024-03-08T14:00:00Z"},
+				ScheduleSlotMinutesParam: {"15"},
+			},
+			wantTracks: []TrackInfo{
+				{Track: "T1", Duration: 240, EstimatedStartTime: at(0, 0)},
+				{Track: "T2", EstimatedStartTime: at(4, 0)},
+				{Track: "Station ID", RowType: RowTypeStationId, EstimatedStartTime: at(4, 0)},
+				{Track: "T3", Duration: 240, EstimatedStartTime: at(4, 0)},
+				{Track: "T4", Duration: 180, EstimatedStartTime: at(8, 0)},
+			},
+			wantSchedule: &ScheduleInfo{ShowStart: showStart, SlotLength: 900, Runtime: 660, Remaining: 240, UnknownDurations: 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trackSchedule, err := NewTrackSchedule(test.query)
+			if err != nil {
+				t.Fatalf("NewTrackSchedule: %v", err)
+			}
+			got := trackSchedule.Apply(NewTrackCollectionInfo(tracks, "show"))
+			if !reflect.DeepEqual(got.Tracks, test.wantTracks) {
+				t.Errorf("Apply tracks:\n got %+v\nwant %+v", got.Tracks, test.wantTracks)
+			}
+			if !reflect.DeepEqual(got.Schedule, test.wantSchedule) {
+				t.Errorf("Apply schedule:\n got %+v\nwant %+v", got.Schedule, test.wantSchedule)
+			}
+		})
+	}
+}
+
+func TestNewTrackScheduleInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"unreadable show start", url.Values{ScheduleShowStartParam: {"2pm"}}},
+		{"zero slot", url.Values{ScheduleShowStartParam: {"14:00"}, ScheduleSlotMinutesParam: {"0"}}},
+		{"slot without show start", url.Values{ScheduleSlotMinutesParam: {"60"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewTrackSchedule(test.query)
+			if _, ok := err.(InvalidRequestError); !ok {
+				t.Errorf("NewTrackSchedule: got error %v, want an invalid request error", err)
+			}
+		})
+	}
+}
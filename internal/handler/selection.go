@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SelectionFromParam       = "from"
+	SelectionToParam         = "to"
+	SelectionIndexesParam    = "indexes"
+	SelectionDiscParam       = "disc"
+	SelectionAddedFromParam  = "addedFrom"
+	SelectionAddedUntilParam = "addedUntil"
+	maximumSelectionIndex    = 10000
+)
+
+var SelectionParams = []string{
+	SelectionFromParam,
+	SelectionToParam,
+	SelectionIndexesParam,
+	SelectionDiscParam,
+	SelectionAddedFromParam,
+	SelectionAddedUntilParam,
+}
+
+// TrackSelection narrows a track collection to the part of it that was
+// played. Disc and added time filters apply first; from, to and indexes are
+// 1-based positions within what remains, so "disc=2&from=3" is the third
+// track of the second disc onwards. Every given parameter narrows the result.
+type TrackSelection struct {
+	from       int
+	to         int
+	indexes    map[int]bool
+	disc       int
+	addedFrom  *time.Time
+	addedUntil *time.Time
+}
+
+func (trackSelection TrackSelection) IsEmpty() bool {
+	return trackSelection.from == 0 &&
+		trackSelection.to == 0 &&
+		trackSelection.indexes == nil &&
+		trackSelection.disc == 0 &&
+		trackSelection.addedFrom == nil &&
+		trackSelection.addedUntil == nil
+}
+
+func NewTrackSelection(query url.Values) (TrackSelection, error) {
+	var trackSelection TrackSelection
+	var err error
+	if trackSelection.from, err = PositiveIntParam(query, SelectionFromParam); err != nil {
+		return trackSelection, err
+	}
+	if trackSelection.to, err = PositiveIntParam(query, SelectionToParam); err != nil {
+		return trackSelection, err
+	}
+	if trackSelection.from > 0 && trackSelection.to > 0 && trackSelection.to < trackSelection.from {
+		return trackSelection, NewInvalidRequestError(fmt.Sprintf("'%s' is before '%s'", SelectionToParam, SelectionFromParam))
+	}
+	if trackSelection.disc, err = PositiveIntParam(query, SelectionDiscParam); err != nil {
+		return trackSelection, err
+	}
+	if value := query.Get(SelectionIndexesParam); value != "" {
+		if trackSelection.indexes, err = parseIndexes(value); err != nil {
+			return trackSelection, err
+		}
+	}
+	if value := query.Get(SelectionAddedFromParam); value != "" {
+		addedFrom, err := parseSelectionTime(value, false)
+		if err != nil {
+			return trackSelection, NewInvalidRequestError(fmt.Sprintf("invalid '%s': %s", SelectionAddedFromParam, value))
+		}
+		trackSelection.addedFrom = &addedFrom
+	}
+	if value := query.Get(SelectionAddedUntilParam); value != "" {
+		addedUntil, err := parseSelectionTime(value, true)
+		if err != nil {
+			return trackSelection, NewInvalidRequestError(fmt.Sprintf("invalid '%s': %s", SelectionAddedUntilParam, value))
+		}
+		trackSelection.addedUntil = &addedUntil
+	}
+	return trackSelection, nil
+}
+
+// Apply returns the selected tracks in their original order. Tracks without
+// a disc number are treated as being on the first disc; tracks without an
+// added time are dropped by an added time filter.
+func (trackSelection TrackSelection) Apply(tracks []TrackInfo) []TrackInfo {
+	if trackSelection.IsEmpty() {
+		return tracks
+	}
+	filtered := make([]TrackInfo, 0, len(tracks))
+	for _, track := range tracks {
+		if trackSelection.disc > 0 && max(track.DiscNumber, 1) != trackSelection.disc {
+			continue
+		}
+		if trackSelection.addedFrom != nil && (track.AddedAt == nil || track.AddedAt.Before(*trackSelection.addedFrom)) {
+			continue
+		}
+		if trackSelection.addedUntil != nil && (track.AddedAt == nil || !track.AddedAt.Before(*trackSelection.addedUntil)) {
+			continue
+		}
+		filtered = append(filtered, track)
+	}
+	selected := make([]TrackInfo, 0, len(filtered))
+	for i, track := range filtered {
+		position := i + 1
+		if trackSelection.from > 0 && position < trackSelection.from {
+			continue
+		}
+		if trackSelection.to > 0 && position > trackSelection.to {
+			continue
+		}
+		if trackSelection.indexes != nil && !trackSelection.indexes[position] {
+			continue
+		}
+		selected = append(selected, track)
+	}
+	return selected
+}
+
+// PositiveIntParam reads an optional whole number parameter of at least 1,
+// which is 0 when the parameter is not given.
+func PositiveIntParam(query url.Values, name string) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		return 0, NewInvalidRequestError(fmt.Sprintf("'%s' must be a positive whole number", name))
+	}
+	return parsed, nil
+}
+
+// Indexes are a comma separated list of positions and ranges, e.g. "1,3,5-7".
+func parseIndexes(value string) (map[int]bool, error) {
+	indexes := make(map[int]bool)
+	invalid := NewInvalidRequestError(fmt.Sprintf("invalid '%s': %s", SelectionIndexesParam, value))
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil || start < 1 || start > maximumSelectionIndex {
+			return nil, invalid
+		}
+		end := start
+		if isRange {
+			end, err = strconv.Atoi(strings.TrimSpace(last))
+			if err != nil || end < start || end > maximumSelectionIndex {
+				return nil, invalid
+			}
+		}
+		for index := start; index <= end; index++ {
+			indexes[index] = true
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, invalid
+	}
+	return indexes, nil
+}
+
+// Times are RFC 3339, or a date in the server's time zone. A date given as
+// the end of a window includes the whole of that day.
+func parseSelectionTime(value string, isEnd bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return parsed, err
+	}
+	if isEnd {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return parsed, nil
+}
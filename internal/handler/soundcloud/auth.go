@@ -0,0 +1,86 @@
+package soundcloud
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+type SoundCloudTokenData struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (soundCloudHandler *SoundCloudHandler) getToken(clientId string, clientSecret string) (string, error) {
+	soundCloudHandler.tokenMutex.Lock()
+	defer soundCloudHandler.tokenMutex.Unlock()
+	nowMilli := time.Now().UTC().UnixMilli()
+	if soundCloudHandler.tokenExpiryTimeMilli-nowMilli <= 5000 {
+		data := url.Values{}
+		data.Set("grant_type", "client_credentials")
+		req, err := http.NewRequest(http.MethodPost, "https://secure.soundcloud.com/oauth/token", strings.NewReader(data.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Add(
+			"Authorization",
+			fmt.Sprintf(
+				"Basic %s",
+				base64.StdEncoding.EncodeToString(
+					[]byte(
+						fmt.Sprintf(
+							"%s:%s",
+							clientId,
+							clientSecret,
+						),
+					),
+				),
+			),
+		)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Accept", "application/json; charset=utf-8")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		var tokenResponse SoundCloudTokenData
+		err = json.Unmarshal(body, &tokenResponse)
+		if err != nil {
+			return "", err
+		}
+		if tokenResponse.AccessToken == "" {
+			return "", errors.New("no token returned from SoundCloud API")
+		}
+		soundCloudHandler.token = tokenResponse.AccessToken
+		soundCloudHandler.tokenExpiryTimeMilli = nowMilli + int64(tokenResponse.ExpiresIn)*1000
+	}
+	return soundCloudHandler.token, nil
+}
+
+// ValidateCredentials reports whether a token can currently be obtained with
+// the configured client credentials. Failing to reach the token endpoint is
+// not an authentication error, as it says nothing about the credentials.
+func (soundCloudHandler *SoundCloudHandler) ValidateCredentials() error {
+	token, err := soundCloudHandler.getToken(soundCloudHandler.clientId, soundCloudHandler.clientSecret)
+	if _, ok := err.(*url.Error); ok {
+		return err
+	}
+	if token == "" || err != nil {
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	return nil
+}
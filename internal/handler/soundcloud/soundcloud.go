@@ -0,0 +1,218 @@
+package soundcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const apiUrlBase = "https://api.soundcloud.com"
+const permalinkUrlBase = "https://soundcloud.com"
+const createdAtLayout = "2006/01/02 15:04:05 -0700"
+
+var numericIdRegex = regexp.MustCompile(`^\d+$`)
+
+// Any other "{user}/{slug}" path is taken to be a track, so sets are matched
+// first. Profile tabs and SoundCloud's own pages share that shape and are
+// excluded.
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPattern(constants.PlaylistRequestType, `^https?://(?:www\.|m\.)?soundcloud\.com/([\w-]+/sets/[\w-]+)`),
+	handler.NewUrlPatternExcluding(
+		constants.TrackRequestType,
+		`^https?://(?:www\.|m\.)?soundcloud\.com/([\w-]+/[\w-]+)/?(?:[?#]|$)`,
+		"likes", "reposts", "albums", "sets", "tracks", "popular-tracks", "toptracks", "playlists",
+		"followers", "following", "comments", "spotlight", "stations",
+		"discover", "stream", "search", "you", "upload", "charts", "pages", "settings",
+		"messages", "notifications", "people", "feed", "tags", "pro", "jobs", "mobile", "terms-of-use",
+	),
+}
+
+func (soundCloudHandler *SoundCloudHandler) Identifier() string {
+	return "soundcloud"
+}
+
+func (soundCloudHandler *SoundCloudHandler) UrlPatterns() []handler.UrlPattern {
+	return urlPatterns
+}
+
+func (soundCloudHandler *SoundCloudHandler) Track(request *http.Request) (trackInfo handler.TrackInfo, err error) {
+	vars := soundCloudHandler.pathParamsProvider(request)
+	trackParamValue := vars[constants.TrackIdentifierParam]
+	token, err := soundCloudHandler.getToken(soundCloudHandler.clientId, soundCloudHandler.clientSecret)
+	if token == "" || err != nil {
+		return handler.EmptyTrackInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	var trackUrl string
+	if numericIdRegex.MatchString(trackParamValue) {
+		trackUrl = fmt.Sprintf("%s/tracks/%s", apiUrlBase, trackParamValue)
+	} else {
+		trackUrl = resolveUrl(fmt.Sprintf("%s/%s", permalinkUrlBase, strings.Trim(trackParamValue, "/")))
+	}
+	var data SoundCloudTrackData
+	statusCode, err := getJson(trackUrl, token, &data)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(trackParamValue)
+	case http.StatusUnauthorized:
+		return handler.EmptyTrackInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	case http.StatusNotFound:
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	default:
+		return handler.EmptyTrackInfo, fmt.Errorf("soundcloud API returned status: %d", statusCode)
+	}
+	if data.Kind != "track" {
+		return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(trackParamValue)
+	}
+	return soundCloudHandler.trackInfoFromSoundCloudTrackData(data, false), nil
+}
+
+// Playlist identifiers are either a numeric playlist ID or the "{user}/sets/{slug}"
+// permalink path, which is resolved to a playlist ID before its tracks are paged.
+func (soundCloudHandler *SoundCloudHandler) Playlist(request *http.Request) (playlistInfo handler.TrackCollectionInfo, err error) {
+	vars := soundCloudHandler.pathParamsProvider(request)
+	playlistParamValue := vars[constants.PlaylistIdentifierParam]
+	token, err := soundCloudHandler.getToken(soundCloudHandler.clientId, soundCloudHandler.clientSecret)
+	if token == "" || err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	playlistUrl := fmt.Sprintf("%s/playlists/%s?show_tracks=false", apiUrlBase, playlistParamValue)
+	if !numericIdRegex.MatchString(playlistParamValue) {
+		playlistUrl = resolveUrl(fmt.Sprintf("%s/%s", permalinkUrlBase, strings.Trim(playlistParamValue, "/")))
+	}
+	var playlistData SoundCloudPlaylistData
+	statusCode, err := getJson(playlistUrl, token, &playlistData)
+	if err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	if err := collectionStatusError(statusCode, playlistParamValue); err != nil {
+		return handler.EmptyTrackCollectionInfo, err
+	}
+	if playlistData.Kind != "playlist" {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(playlistParamValue)
+	}
+	isSingle := playlistData.isSingle()
+	nextUrl := fmt.Sprintf(
+		"%s/playlists/%d/tracks?linked_partitioning=true&limit=200",
+		apiUrlBase,
+		playlistData.ID,
+	)
+	trackInfos := make([]handler.TrackInfo, 0)
+	for nextUrl != "" {
+		var data SoundCloudTracksPageData
+		statusCode, err := getJson(nextUrl, token, &data)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		if err := collectionStatusError(statusCode, playlistParamValue); err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		for _, entry := range data.Collection {
+			trackInfos = append(
+				trackInfos,
+				soundCloudHandler.trackInfoFromSoundCloudTrackData(entry, isSingle),
+			)
+		}
+		nextUrl = data.NextHref
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, playlistParamValue), nil
+}
+
+// Tracks do not record what kind of release they are part of, so only tracks
+// read from a set that SoundCloud types as a single are marked as singles.
+func (soundCloudHandler *SoundCloudHandler) trackInfoFromSoundCloudTrackData(soundCloudTrackData SoundCloudTrackData, isSingle bool) handler.TrackInfo {
+	artist := soundCloudTrackData.User.Username
+	album := ""
+	releaseDate := ""
+	if metadata := soundCloudTrackData.PublisherMetadata; metadata != nil {
+		if metadata.Artist != "" {
+			artist = metadata.Artist
+		}
+		album = metadata.AlbumTitle
+		releaseDate = releaseDateFromTimestamp(metadata.ReleaseDate)
+	}
+	if releaseDate == "" {
+		if createdAt, err := time.Parse(createdAtLayout, soundCloudTrackData.CreatedAt); err == nil {
+			releaseDate = createdAt.UTC().Format(time.DateOnly)
+		}
+	}
+	trackInfo := handler.NewTrackInfo(
+		artist,
+		soundCloudTrackData.Title,
+		album,
+		isSingle,
+		false,
+	)
+	trackInfo = soundCloudHandler.newReleaseRules.Apply(trackInfo, releaseDate, handler.ReleaseDatePrecisionDay)
+	trackInfo.Link = soundCloudTrackData.PermalinkUrl
+	trackInfo.Duration = handler.SecondsFromMilliseconds(soundCloudTrackData.Duration)
+	return trackInfo
+}
+
+func releaseDateFromTimestamp(timestamp string) string {
+	if date, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return date.UTC().Format(time.DateOnly)
+	}
+	if _, err := time.Parse(time.DateOnly, timestamp); err == nil {
+		return timestamp
+	}
+	return ""
+}
+
+func resolveUrl(permalinkUrl string) string {
+	return fmt.Sprintf(
+		"%s/resolve?url=%s",
+		apiUrlBase,
+		url.QueryEscape(permalinkUrl),
+	)
+}
+
+func collectionStatusError(statusCode int, collectionId string) error {
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusBadRequest:
+		return handler.NewInvalidTrackCollectionIdError(collectionId)
+	case http.StatusUnauthorized:
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	case http.StatusNotFound:
+		return handler.NewTrackCollectionNotFoundError(collectionId)
+	default:
+		return fmt.Errorf("soundcloud API returned status: %d", statusCode)
+	}
+}
+
+// Resolve requests answer with a redirect to the resource itself, which the
+// default client follows, so callers only ever see the final status.
+func getJson(url string, token string, target any) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, handler.NewInternalError(err.Error())
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("OAuth %s", token))
+	req.Header.Add("Accept", "application/json; charset=utf-8")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, json.Unmarshal(body, target)
+}
@@ -0,0 +1,72 @@
+package soundcloud
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type SoundCloudTrackData struct {
+	Kind              string `json:"kind"`
+	Title             string `json:"title"`
+	CreatedAt         string `json:"created_at"`
+	Duration          int64  `json:"duration"`
+	PermalinkUrl      string `json:"permalink_url"`
+	PublisherMetadata *struct {
+		Artist      string `json:"artist"`
+		AlbumTitle  string `json:"album_title"`
+		ReleaseDate string `json:"release_date"`
+	} `json:"publisher_metadata"`
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+}
+
+type SoundCloudPlaylistData struct {
+	Kind         string `json:"kind"`
+	ID           int64  `json:"id"`
+	SetType      string `json:"set_type"`
+	PlaylistType string `json:"playlist_type"`
+}
+
+// Sets are typed as albums, EPs, singles and so on by set_type, or by the
+// older playlist_type on sets that predate it.
+func (soundCloudPlaylistData SoundCloudPlaylistData) isSingle() bool {
+	if soundCloudPlaylistData.SetType != "" {
+		return soundCloudPlaylistData.SetType == "single"
+	}
+	return soundCloudPlaylistData.PlaylistType == "single"
+}
+
+type SoundCloudTracksPageData struct {
+	NextHref   string                `json:"next_href"`
+	Collection []SoundCloudTrackData `json:"collection"`
+}
+
+type SoundCloudHandler struct {
+	clientId             string
+	clientSecret         string
+	token                string
+	tokenExpiryTimeMilli int64
+	tokenMutex           sync.Mutex
+	pathParamsProvider   func(*http.Request) map[string]string
+	newReleaseRules      *newrelease.Rules
+}
+
+func NewSoundCloudHandler(
+	clientId string,
+	clientSecret string,
+	newReleaseRules *newrelease.Rules,
+) *SoundCloudHandler {
+	return &SoundCloudHandler{
+		clientId:             clientId,
+		clientSecret:         clientSecret,
+		token:                "",
+		tokenExpiryTimeMilli: 0,
+		pathParamsProvider:   mux.Vars,
+		newReleaseRules:      newReleaseRules,
+	}
+}
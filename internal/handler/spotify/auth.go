@@ -10,6 +10,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
 )
 
 type SpotifyTokenData struct {
@@ -19,6 +21,8 @@ type SpotifyTokenData struct {
 }
 
 func (spotifyHandler *SpotifyHandler) getToken(clientId string, clientSecret string) (string, error) {
+	spotifyHandler.tokenMutex.Lock()
+	defer spotifyHandler.tokenMutex.Unlock()
 	nowMilli := time.Now().UTC().UnixMilli()
 	if spotifyHandler.tokenExpiryTimeMilli-nowMilli <= 5000 {
 		data := url.Values{}
@@ -65,3 +69,17 @@ func (spotifyHandler *SpotifyHandler) getToken(clientId string, clientSecret str
 	}
 	return spotifyHandler.token, nil
 }
+
+// ValidateCredentials reports whether a token can currently be obtained with
+// the configured client credentials. Failing to reach the token endpoint is
+// not an authentication error, as it says nothing about the credentials.
+func (spotifyHandler *SpotifyHandler) ValidateCredentials() error {
+	token, err := spotifyHandler.getToken(spotifyHandler.clientId, spotifyHandler.clientSecret)
+	if _, ok := err.(*url.Error); ok {
+		return err
+	}
+	if token == "" || err != nil {
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	return nil
+}
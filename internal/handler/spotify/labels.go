@@ -0,0 +1,77 @@
+package spotify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	albumBatchSize      = 20
+	albumLabelCacheSize = 5000
+)
+
+// Track and playlist entries only carry a simplified album, without its
+// label. Labels are read with one request per 20 albums and the most recently
+// used are cached, so playlists that revisit an album or are fetched again
+// cost no extra calls.
+// Labels are informational, so a failed lookup leaves them empty rather than
+// failing the request.
+func (spotifyHandler *SpotifyHandler) albumLabels(albumIds []string, token string) map[string]string {
+	labels := make(map[string]string)
+	uncachedIds := make([]string, 0)
+	for _, albumId := range albumIds {
+		if albumId == "" {
+			continue
+		}
+		if label, ok := spotifyHandler.albumLabelCache.Get(albumId); ok {
+			labels[albumId] = label
+		} else if _, pending := labels[albumId]; !pending {
+			labels[albumId] = ""
+			uncachedIds = append(uncachedIds, albumId)
+		}
+	}
+	for start := 0; start < len(uncachedIds); start += albumBatchSize {
+		data, err := getAlbums(uncachedIds[start:min(start+albumBatchSize, len(uncachedIds))], token)
+		if err != nil {
+			break
+		}
+		for _, album := range data.Albums {
+			if album != nil {
+				labels[album.ID] = album.Label
+				spotifyHandler.albumLabelCache.Add(album.ID, album.Label)
+			}
+		}
+	}
+	return labels
+}
+
+func getAlbums(albumIds []string, token string) (SpotifyAlbumsData, error) {
+	url := fmt.Sprintf(
+		"https://api.spotify.com/v1/albums?ids=%s&market=%s",
+		strings.Join(albumIds, ","),
+		market,
+	)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return SpotifyAlbumsData{}, err
+	}
+	addAuthHeader(req, token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return SpotifyAlbumsData{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return SpotifyAlbumsData{}, fmt.Errorf("spotify API returned status: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return SpotifyAlbumsData{}, err
+	}
+	var data SpotifyAlbumsData
+	err = json.Unmarshal(body, &data)
+	return data, err
+}
@@ -0,0 +1,59 @@
+package spotify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const searchResultLimit = 5
+
+// Search looks up tracks by artist and title for callers that only have
+// free text, returning Spotify's best candidates in relevance order.
+func (spotifyHandler *SpotifyHandler) Search(artist string, title string) ([]handler.TrackInfo, error) {
+	token, err := spotifyHandler.getToken(spotifyHandler.clientId, spotifyHandler.clientSecret)
+	if token == "" || err != nil {
+		return nil, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	query := fmt.Sprintf("track:%s", strings.ReplaceAll(title, ":", " "))
+	if artist != "" {
+		query = fmt.Sprintf("%s artist:%s", query, strings.ReplaceAll(artist, ":", " "))
+	}
+	values := url.Values{}
+	values.Set("q", query)
+	values.Set("type", "track")
+	values.Set("market", market)
+	values.Set("limit", fmt.Sprint(searchResultLimit))
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://api.spotify.com/v1/search?%s", values.Encode()), nil)
+	if err != nil {
+		return nil, handler.NewInternalError(err.Error())
+	}
+	addAuthHeader(req, token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("spotify API returned status: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var data SpotifySearchData
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return nil, err
+	}
+	trackInfos := make([]handler.TrackInfo, len(data.Tracks.Items))
+	for i, item := range data.Tracks.Items {
+		trackInfos[i] = spotifyHandler.trackInfoFromSpotifyTrackData(item)
+	}
+	return trackInfos, nil
+}
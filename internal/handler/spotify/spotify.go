@@ -6,16 +6,37 @@ import (
 	"io"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/captaincoordinates/cick-playlister/internal/constants"
 	"github.com/captaincoordinates/cick-playlister/internal/handler"
 )
 
+const market = "CA"
+const episodeItemType = "episode"
+
+// Shared links carry tracking parameters (?si=) and may be localised with an
+// /intl-xx/ prefix. spotify: URIs are what the desktop app copies.
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPattern(constants.PlaylistRequestType, `^https?://open\.spotify\.com/(?:intl-[a-z]{2}(?:-[A-Za-z]{2})?/)?(?:embed/)?playlist/(\w+)`),
+	handler.NewUrlPattern(constants.AlbumRequestType, `^https?://open\.spotify\.com/(?:intl-[a-z]{2}(?:-[A-Za-z]{2})?/)?(?:embed/)?album/(\w+)`),
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://open\.spotify\.com/(?:intl-[a-z]{2}(?:-[A-Za-z]{2})?/)?(?:embed/)?track/(\w+)`),
+	handler.NewUrlPattern(constants.EpisodeRequestType, `^https?://open\.spotify\.com/(?:intl-[a-z]{2}(?:-[A-Za-z]{2})?/)?(?:embed/)?episode/(\w+)`),
+	handler.NewUrlPattern(constants.ShowRequestType, `^https?://open\.spotify\.com/(?:intl-[a-z]{2}(?:-[A-Za-z]{2})?/)?(?:embed/)?show/(\w+)`),
+	handler.NewUrlPattern(constants.PlaylistRequestType, `^spotify:(?:user:[^:]+:)?playlist:(\w+)$`),
+	handler.NewUrlPattern(constants.AlbumRequestType, `^spotify:album:(\w+)$`),
+	handler.NewUrlPattern(constants.TrackRequestType, `^spotify:track:(\w+)$`),
+	handler.NewUrlPattern(constants.EpisodeRequestType, `^spotify:episode:(\w+)$`),
+	handler.NewUrlPattern(constants.ShowRequestType, `^spotify:show:(\w+)$`),
+}
+
 func (spotifyHandler *SpotifyHandler) Identifier() string {
 	return "spotify"
 }
 
+func (spotifyHandler *SpotifyHandler) UrlPatterns() []handler.UrlPattern {
+	return urlPatterns
+}
+
 func (spotifyHandler *SpotifyHandler) Track(request *http.Request) (trackInfo handler.TrackInfo, err error) {
 	vars := spotifyHandler.pathParamsProvider(request)
 	trackParamValue := vars[constants.TrackIdentifierParam]
@@ -56,7 +77,9 @@ func (spotifyHandler *SpotifyHandler) Track(request *http.Request) (trackInfo ha
 	if err != nil {
 		return handler.EmptyTrackInfo, err
 	}
-	return spotifyHandler.trackInfoFromSpotifyTrackData(data), nil
+	trackInfo = spotifyHandler.trackInfoFromSpotifyTrackData(data)
+	trackInfo.Label = spotifyHandler.albumLabels([]string{data.Album.ID}, token)[data.Album.ID]
+	return trackInfo, nil
 }
 
 func (spotifyHandler *SpotifyHandler) Album(request *http.Request) (albumInfo handler.TrackCollectionInfo, err error) {
@@ -70,6 +93,7 @@ func (spotifyHandler *SpotifyHandler) Album(request *http.Request) (albumInfo ha
 		"https://api.spotify.com/v1/albums/%s",
 		albumParamValue,
 	)
+	label := ""
 	trackInfos := make([]handler.TrackInfo, 0)
 	for nextUrl != "" {
 		req, err := http.NewRequest(http.MethodGet, nextUrl, nil)
@@ -101,22 +125,28 @@ func (spotifyHandler *SpotifyHandler) Album(request *http.Request) (albumInfo ha
 		if err != nil {
 			return handler.EmptyTrackCollectionInfo, err
 		}
+		if data.Label != "" {
+			label = data.Label
+		}
 		for _, entry := range data.Tracks.Items {
 			artistNames := make([]string, len(entry.Artists))
 			for i, artist := range entry.Artists {
 				artistNames[i] = artist.Name
 			}
 			artists := strings.Join(artistNames, ", ")
-			trackInfos = append(
-				trackInfos,
-				handler.NewTrackInfo(
-					artists,
-					entry.Name,
-					data.Name,
-					false,
-					spotifyHandler.trackIsNew(data.ReleaseDate, data.ReleaseDatePrecision),
-				),
+			trackInfo := handler.NewTrackInfo(
+				artists,
+				entry.Name,
+				data.Name,
+				false,
+				false,
 			)
+			trackInfo = spotifyHandler.newReleaseRules.Apply(trackInfo, data.ReleaseDate, data.ReleaseDatePrecision)
+			trackInfo.Label = label
+			trackInfo.Link = entry.ExternalUrls.Spotify
+			trackInfo.DiscNumber = entry.DiscNumber
+			trackInfo.Duration = handler.SecondsFromMilliseconds(entry.DurationMs)
+			trackInfos = append(trackInfos, trackInfo)
 		}
 		nextUrl = data.Tracks.Next
 	}
@@ -131,11 +161,13 @@ func (spotifyHandler *SpotifyHandler) Playlist(request *http.Request) (playlistI
 		return handler.EmptyTrackCollectionInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
 	}
 	nextUrl := fmt.Sprintf(
-		"https://api.spotify.com/v1/playlists/%s/tracks?fields=%s",
+		"https://api.spotify.com/v1/playlists/%s/tracks?market=%s&additional_types=track,episode&fields=%s",
 		playlistParamValue,
-		"next,items(track(name,artists(name),album(name,album_type,release_date,release_date_precision))",
+		market,
+		"next,items(added_at,track(type,name,disc_number,duration_ms,external_urls,release_date,release_date_precision,show(name),artists(name),album(id,name,album_type,release_date,release_date_precision))",
 	)
 	trackInfos := make([]handler.TrackInfo, 0)
+	albumIds := make([]string, 0)
 	for nextUrl != "" {
 		req, err := http.NewRequest(http.MethodGet, nextUrl, nil)
 		if err != nil {
@@ -167,14 +199,160 @@ func (spotifyHandler *SpotifyHandler) Playlist(request *http.Request) (playlistI
 			return handler.EmptyTrackCollectionInfo, err
 		}
 		for _, entry := range data.Items {
+			if entry.Track == nil {
+				continue
+			}
+			var trackInfo handler.TrackInfo
+			switch entry.Track.Type {
+			case episodeItemType:
+				trackInfo = spotifyHandler.trackInfoFromSpotifyEpisodeData(
+					SpotifyEpisodeData{
+						Name:                 entry.Track.Name,
+						DurationMs:           entry.Track.DurationMs,
+						ExternalUrls:         entry.Track.ExternalUrls,
+						ReleaseDate:          entry.Track.ReleaseDate,
+						ReleaseDatePrecision: entry.Track.ReleaseDatePrecision,
+						Show:                 entry.Track.Show,
+					},
+					entry.Track.Show.Name,
+				)
+			default:
+				trackInfo = spotifyHandler.trackInfoFromSpotifyTrackData(entry.Track.SpotifyTrackData)
+			}
+			trackInfo.AddedAt = entry.AddedAt
+			trackInfos = append(trackInfos, trackInfo)
+			albumIds = append(albumIds, entry.Track.Album.ID)
+		}
+		nextUrl = data.Next
+	}
+	labels := spotifyHandler.albumLabels(albumIds, token)
+	for i := range trackInfos {
+		trackInfos[i].Label = labels[albumIds[i]]
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, playlistParamValue), nil
+}
+
+func (spotifyHandler *SpotifyHandler) Episode(request *http.Request) (trackInfo handler.TrackInfo, err error) {
+	vars := spotifyHandler.pathParamsProvider(request)
+	episodeParamValue := vars[constants.EpisodeIdentifierParam]
+	token, err := spotifyHandler.getToken(spotifyHandler.clientId, spotifyHandler.clientSecret)
+	if token == "" || err != nil {
+		return handler.EmptyTrackInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	url := fmt.Sprintf(
+		"https://api.spotify.com/v1/episodes/%s?market=%s",
+		episodeParamValue,
+		market,
+	)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return handler.EmptyTrackInfo, handler.NewInternalError(err.Error())
+	}
+	addAuthHeader(req, token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(episodeParamValue)
+		case http.StatusNotFound:
+			return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(episodeParamValue)
+		default:
+			return handler.EmptyTrackInfo, fmt.Errorf("spotify API returned status: %d", resp.StatusCode)
+		}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	var data SpotifyEpisodeData
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	return spotifyHandler.trackInfoFromSpotifyEpisodeData(data, data.Show.Name), nil
+}
+
+func (spotifyHandler *SpotifyHandler) Show(request *http.Request) (showInfo handler.TrackCollectionInfo, err error) {
+	vars := spotifyHandler.pathParamsProvider(request)
+	showParamValue := vars[constants.ShowIdentifierParam]
+	token, err := spotifyHandler.getToken(spotifyHandler.clientId, spotifyHandler.clientSecret)
+	if token == "" || err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	nextUrl := fmt.Sprintf(
+		"https://api.spotify.com/v1/shows/%s?market=%s",
+		showParamValue,
+		market,
+	)
+	showName := ""
+	isShowPage := true
+	trackInfos := make([]handler.TrackInfo, 0)
+	for nextUrl != "" {
+		req, err := http.NewRequest(http.MethodGet, nextUrl, nil)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, handler.NewInternalError(err.Error())
+		}
+		addAuthHeader(req, token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			switch resp.StatusCode {
+			case http.StatusBadRequest:
+				return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(showParamValue)
+			case http.StatusNotFound:
+				return handler.EmptyTrackCollectionInfo, handler.NewTrackCollectionNotFoundError(showParamValue)
+			default:
+				return handler.EmptyTrackCollectionInfo, fmt.Errorf("spotify API returned status: %d", resp.StatusCode)
+			}
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		// the first response is the show itself, later pages are bare episode pages
+		var page SpotifyEpisodesPageData
+		if isShowPage {
+			var data SpotifyShowData
+			err = json.Unmarshal(body, &data)
+			showName = data.Name
+			page = data.Episodes
+			isShowPage = false
+		} else {
+			err = json.Unmarshal(body, &page)
+		}
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		for _, entry := range page.Items {
 			trackInfos = append(
 				trackInfos,
-				spotifyHandler.trackInfoFromSpotifyTrackData(entry.Track),
+				spotifyHandler.trackInfoFromSpotifyEpisodeData(entry, showName),
 			)
 		}
-		nextUrl = data.Next
+		nextUrl = page.Next
 	}
-	return handler.NewTrackCollectionInfo(trackInfos, playlistParamValue), nil
+	return handler.NewTrackCollectionInfo(trackInfos, showParamValue), nil
+}
+
+func (spotifyHandler *SpotifyHandler) trackInfoFromSpotifyEpisodeData(spotifyEpisodeData SpotifyEpisodeData, showName string) handler.TrackInfo {
+	trackInfo := handler.NewTrackInfo(
+		showName,
+		spotifyEpisodeData.Name,
+		"",
+		false,
+		false,
+	)
+	trackInfo = spotifyHandler.newReleaseRules.Apply(trackInfo, spotifyEpisodeData.ReleaseDate, spotifyEpisodeData.ReleaseDatePrecision)
+	trackInfo.Link = spotifyEpisodeData.ExternalUrls.Spotify
+	trackInfo.Duration = handler.SecondsFromMilliseconds(spotifyEpisodeData.DurationMs)
+	return trackInfo
 }
 
 func (spotifyHandler *SpotifyHandler) trackInfoFromSpotifyTrackData(spotifyTrackData SpotifyTrackData) handler.TrackInfo {
@@ -183,50 +361,18 @@ func (spotifyHandler *SpotifyHandler) trackInfoFromSpotifyTrackData(spotifyTrack
 		artistNames[i] = artist.Name
 	}
 	artists := strings.Join(artistNames, ", ")
-	return handler.NewTrackInfo(
+	trackInfo := handler.NewTrackInfo(
 		artists,
 		spotifyTrackData.Name,
 		spotifyTrackData.Album.Name,
 		spotifyTrackData.Album.AlbumType == "single",
-		spotifyHandler.trackIsNew(spotifyTrackData.Album.ReleaseDate, spotifyTrackData.Album.ReleaseDatePrecision),
+		false,
 	)
-}
-
-func (spotifyHandler *SpotifyHandler) trackIsNew(releaseDate string, releaseDatePrecision string) bool {
-	dateLocation, _ := time.LoadLocation("UTC")
-	var date time.Time
-	var err error
-	switch releaseDatePrecision {
-	case "day":
-		date, err = time.ParseInLocation(
-			time.DateOnly,
-			releaseDate,
-			dateLocation,
-		)
-	case "month":
-		date, err = time.ParseInLocation(
-			time.DateOnly,
-			fmt.Sprintf(
-				"%s-01",
-				releaseDate,
-			),
-			dateLocation,
-		)
-	case "year":
-		date, err = time.ParseInLocation(
-			time.DateOnly,
-			fmt.Sprintf(
-				"%s-01-01",
-				releaseDate,
-			),
-			dateLocation,
-		)
-	}
-	if err != nil {
-		fmt.Println(err.Error())
-		return false
-	}
-	return time.Now().UTC().AddDate(0, 0, -int(spotifyHandler.newReleaseDays)).Before(date)
+	trackInfo = spotifyHandler.newReleaseRules.Apply(trackInfo, spotifyTrackData.Album.ReleaseDate, spotifyTrackData.Album.ReleaseDatePrecision)
+	trackInfo.Link = spotifyTrackData.ExternalUrls.Spotify
+	trackInfo.DiscNumber = spotifyTrackData.DiscNumber
+	trackInfo.Duration = handler.SecondsFromMilliseconds(spotifyTrackData.DurationMs)
+	return trackInfo
 }
 
 func addAuthHeader(request *http.Request, token string) {
@@ -2,16 +2,29 @@ package spotify
 
 import (
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/cache"
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
 
 	"github.com/gorilla/mux"
 )
 
+type SpotifyExternalUrlsData struct {
+	Spotify string `json:"spotify"`
+}
+
 type SpotifyTrackData struct {
 	Artists []struct {
 		Name string `json:"name"`
 	} `json:"artists"`
-	Name  string `json:"name"`
-	Album struct {
+	Name         string                  `json:"name"`
+	DiscNumber   int                     `json:"disc_number"`
+	DurationMs   int64                   `json:"duration_ms"`
+	ExternalUrls SpotifyExternalUrlsData `json:"external_urls"`
+	Album        struct {
+		ID                   string `json:"id"`
 		Name                 string `json:"name"`
 		ReleaseDate          string `json:"release_date"`
 		ReleaseDatePrecision string `json:"release_date_precision"`
@@ -19,15 +32,50 @@ type SpotifyTrackData struct {
 	} `json:"album"`
 }
 
+type SpotifyEpisodeData struct {
+	Name                 string                  `json:"name"`
+	DurationMs           int64                   `json:"duration_ms"`
+	ExternalUrls         SpotifyExternalUrlsData `json:"external_urls"`
+	ReleaseDate          string                  `json:"release_date"`
+	ReleaseDatePrecision string                  `json:"release_date_precision"`
+	Show                 struct {
+		Name string `json:"name"`
+	} `json:"show"`
+}
+
+// Playlist items are tracks or, when requested with additional_types, podcast
+// episodes. Type identifies which of the embedded representations applies.
+type SpotifyPlaylistItemData struct {
+	Type string `json:"type"`
+	SpotifyTrackData
+	ReleaseDate          string `json:"release_date"`
+	ReleaseDatePrecision string `json:"release_date_precision"`
+	Show                 struct {
+		Name string `json:"name"`
+	} `json:"show"`
+}
+
 type SpotifyPlaylistData struct {
 	Next  string `json:"next"`
 	Items []struct {
-		Track SpotifyTrackData `json:"track"`
+		AddedAt *time.Time               `json:"added_at"`
+		Track   *SpotifyPlaylistItemData `json:"track"`
 	} `json:"items"`
 }
 
+type SpotifyEpisodesPageData struct {
+	Next  string               `json:"next"`
+	Items []SpotifyEpisodeData `json:"items"`
+}
+
+type SpotifyShowData struct {
+	Name     string                  `json:"name"`
+	Episodes SpotifyEpisodesPageData `json:"episodes"`
+}
+
 type SpotifyAlbumData struct {
 	Name                 string `json:"name"`
+	Label                string `json:"label"`
 	ReleaseDate          string `json:"release_date"`
 	ReleaseDatePrecision string `json:"release_date_precision"`
 	Tracks               struct {
@@ -36,31 +84,52 @@ type SpotifyAlbumData struct {
 			Artists []struct {
 				Name string `json:"name"`
 			} `json:"artists"`
-			Name string `json:"name"`
+			Name         string                  `json:"name"`
+			DiscNumber   int                     `json:"disc_number"`
+			DurationMs   int64                   `json:"duration_ms"`
+			ExternalUrls SpotifyExternalUrlsData `json:"external_urls"`
 		} `json:"items"`
 	} `json:"tracks"`
 }
 
+// Albums requested by ID are returned in request order, with null entries for
+// IDs that were not found.
+type SpotifyAlbumsData struct {
+	Albums []*struct {
+		ID    string `json:"id"`
+		Label string `json:"label"`
+	} `json:"albums"`
+}
+
 type SpotifyHandler struct {
 	clientId             string
 	clientSecret         string
 	token                string
 	tokenExpiryTimeMilli int64
+	tokenMutex           sync.Mutex
+	albumLabelCache      *cache.LRU[string, string]
 	pathParamsProvider   func(*http.Request) map[string]string
-	newReleaseDays       uint
+	newReleaseRules      *newrelease.Rules
 }
 
 func NewSpotifyHandler(
 	clientId string,
 	clientSecret string,
-	newReleaseDays uint,
+	newReleaseRules *newrelease.Rules,
 ) *SpotifyHandler {
 	return &SpotifyHandler{
 		clientId:             clientId,
 		clientSecret:         clientSecret,
 		token:                "",
 		tokenExpiryTimeMilli: 0,
+		albumLabelCache:      cache.NewLRU[string, string](albumLabelCacheSize),
 		pathParamsProvider:   mux.Vars,
-		newReleaseDays:       newReleaseDays,
+		newReleaseRules:      newReleaseRules,
 	}
 }
+
+type SpotifySearchData struct {
+	Tracks struct {
+		Items []SpotifyTrackData `json:"items"`
+	} `json:"tracks"`
+}
@@ -0,0 +1,118 @@
+package textimport
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/tracklist"
+)
+
+const (
+	textParam          = "text"
+	enrichParam        = "enrich"
+	maximumUploadBytes = 1024 * 1024
+	// below this a row is flagged for the host to review
+	lowConfidenceThreshold = 0.6
+	// titles matter more than artists, which are often credited differently
+	titleWeight = 0.6
+)
+
+func (textImportHandler *TextImportHandler) Identifier() string {
+	return "text"
+}
+
+// ValidateCredentials checks the credentials of the search used by
+// enrich=true, as plain parsing needs none.
+func (textImportHandler *TextImportHandler) ValidateCredentials() error {
+	return textImportHandler.credentialsValidator()
+}
+
+// Upload parses pasted free text, one "Artist - Title" track per line, from
+// the "text" form value. With enrich=true, each line is looked up to fill the
+// album, single and new release details. Every row reports a confidence score,
+// and rows that could not be looked up report why and keep the parsed text.
+func (textImportHandler *TextImportHandler) Upload(request *http.Request) (uploadInfo handler.TrackCollectionInfo, err error) {
+	if err := request.ParseMultipartForm(maximumUploadBytes); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(err.Error())
+	}
+	if request.MultipartForm != nil {
+		defer request.MultipartForm.RemoveAll()
+	}
+	text := request.FormValue(textParam)
+	if strings.TrimSpace(text) == "" {
+		return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError("no text provided")
+	}
+	enrich := request.FormValue(enrichParam) == "true"
+	lines := tracklist.Parse(text)
+	trackInfos := make([]handler.TrackInfo, 0, len(lines))
+	var searchErr error
+	for _, line := range lines {
+		trackInfo := handler.NewTrackInfo(
+			line.Artist,
+			line.Title,
+			line.Album,
+			false,
+			false,
+		)
+		trackInfo = textImportHandler.newReleaseRules.Apply(trackInfo, "", "")
+		confidence := line.Confidence
+		if enrich && searchErr == nil {
+			var candidates []handler.TrackInfo
+			candidates, searchErr = textImportHandler.trackSearcher(line.Artist, line.Title)
+			if _, ok := searchErr.(handler.HandlerAuthenticationError); ok {
+				return handler.EmptyTrackCollectionInfo, searchErr
+			}
+			if searchErr == nil {
+				match, matchConfidence := bestMatch(line, candidates)
+				// a weak match is more likely a different track than a better
+				// description of this one, so the host's text is kept
+				if matchConfidence >= lowConfidenceThreshold {
+					trackInfo = match
+				}
+				confidence = matchConfidence
+			}
+		}
+		// the rows after a failed search are not searched either, as a rate
+		// limit or network failure would only fail again
+		if enrich && searchErr != nil {
+			trackInfo.LookupError = searchErr.Error()
+		}
+		confidence = math.Round(confidence*100) / 100
+		trackInfo.Confidence = &confidence
+		trackInfo.LowConfidence = confidence < lowConfidenceThreshold
+		trackInfos = append(trackInfos, trackInfo)
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, textParam), nil
+}
+
+// Lines without a separator have no artist to compare, so the title alone
+// decides but can never reach full confidence. Hosts sometimes write
+// "Title - Artist", so the swapped reading is scored too.
+func bestMatch(line tracklist.Line, candidates []handler.TrackInfo) (handler.TrackInfo, float64) {
+	best := handler.EmptyTrackInfo
+	bestScore := 0.0
+	for _, candidate := range candidates {
+		score := max(
+			matchScore(line.Artist, line.Title, line.Confidence, candidate),
+			matchScore(line.Title, line.Artist, line.Confidence, candidate),
+		)
+		if score > bestScore {
+			best = candidate
+			bestScore = score
+		}
+	}
+	return best, bestScore
+}
+
+func matchScore(artist string, title string, separatorConfidence float64, candidate handler.TrackInfo) float64 {
+	titleScore := tracklist.Similarity(title, candidate.Track)
+	artistScore := tracklist.Similarity(artist, candidate.Artist)
+	// a host will usually name only the primary of several credited artists
+	for _, candidateArtist := range strings.Split(candidate.Artist, ", ") {
+		artistScore = max(artistScore, tracklist.Similarity(artist, candidateArtist))
+	}
+	return titleWeight*titleScore + (1-titleWeight)*separatorConfidence*artistScore
+}
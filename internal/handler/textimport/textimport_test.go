@@ -0,0 +1,104 @@
+package textimport
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+	"github.com/captaincoordinates/cick-playlister/internal/tracklist"
+)
+
+func TestBestMatch(t *testing.T) {
+	river := handler.NewTrackInfo("Joni Mitchell", "River", "Blue", false, false)
+	stand := handler.NewTrackInfo("Ben E. King", "Stand by Me", "Don't Play That Song!", false, false)
+	duet := handler.NewTrackInfo("Joni Mitchell, James Taylor", "River", "Blue", false, false)
+	tests := []struct {
+		name       string
+		line       tracklist.Line
+		candidates []handler.TrackInfo
+		want       handler.TrackInfo
+		wantScore  float64
+	}{
+		{
+			name:       "artist - title",
+			line:       tracklist.Line{Artist: "Joni Mitchell", Title: "River", Confidence: 1},
+			candidates: []handler.TrackInfo{stand, river},
+			want:       river,
+			wantScore:  1,
+		},
+		{
+			name:       "swapped title - artist",
+			line:       tracklist.Line{Artist: "River", Title: "Joni Mitchell", Confidence: 1},
+			candidates: []handler.TrackInfo{river},
+			want:       river,
+			wantScore:  1,
+		},
+		{
+			name:       "primary of several artists",
+			line:       tracklist.Line{Artist: "Joni Mitchell", Title: "River", Confidence: 1},
+			candidates: []handler.TrackInfo{duet},
+			want:       duet,
+			wantScore:  1,
+		},
+		{
+			name:       "no separator",
+			line:       tracklist.Line{Title: "River", Confidence: 0},
+			candidates: []handler.TrackInfo{river},
+			want:       river,
+			wantScore:  titleWeight,
+		},
+		{
+			name:       "no candidates",
+			line:       tracklist.Line{Artist: "Joni Mitchell", Title: "River", Confidence: 1},
+			candidates: nil,
+			want:       handler.EmptyTrackInfo,
+			wantScore:  0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, score := bestMatch(test.line, test.candidates)
+			if !reflect.DeepEqual(got, test.want) || score != test.wantScore {
+				t.Errorf("bestMatch:\n got %+v, %v\nwant %+v, %v", got, score, test.want, test.wantScore)
+			}
+		})
+	}
+}
+
+func TestUploadWithFailedSearch(t *testing.T) {
+	searches := 0
+	textImportHandler := NewTextImportHandler(
+		func(string, string) ([]handler.TrackInfo, error) {
+			searches++
+			return nil, errors.New("spotify API returned status: 429")
+		},
+		func() error { return nil },
+		newrelease.NewRules(0, "", "", nil, nil),
+	)
+	form := url.Values{}
+	form.Set(textParam, "Joni Mitchell - River\nStand by Me by Ben E. King")
+	form.Set(enrichParam, "true")
+	request := httptest.NewRequest("POST", "/text/upload", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	uploadInfo, err := textImportHandler.Upload(request)
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if searches != 1 {
+		t.Errorf("Upload: got %d searches, want 1", searches)
+	}
+	wantConfidences := []float64{1, 0.8}
+	for i, track := range uploadInfo.Tracks {
+		if track.LookupError != "spotify API returned status: 429" {
+			t.Errorf("Upload: track %d got lookup error %q", i, track.LookupError)
+		}
+		if track.Confidence == nil || *track.Confidence != wantConfidences[i] {
+			t.Errorf("Upload: track %d got confidence %v, want %v", i, track.Confidence, wantConfidences[i])
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package textimport
+
+import (
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+)
+
+type TextImportHandler struct {
+	trackSearcher        func(string, string) ([]handler.TrackInfo, error)
+	credentialsValidator func() error
+	newReleaseRules      *newrelease.Rules
+}
+
+func NewTextImportHandler(
+	trackSearcher func(string, string) ([]handler.TrackInfo, error),
+	credentialsValidator func() error,
+	newReleaseRules *newrelease.Rules,
+) *TextImportHandler {
+	return &TextImportHandler{
+		trackSearcher:        trackSearcher,
+		credentialsValidator: credentialsValidator,
+		newReleaseRules:      newReleaseRules,
+	}
+}
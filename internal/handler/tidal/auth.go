@@ -0,0 +1,85 @@
+package tidal
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+type TidalTokenData struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (tidalHandler *TidalHandler) getToken(clientId string, clientSecret string) (string, error) {
+	tidalHandler.tokenMutex.Lock()
+	defer tidalHandler.tokenMutex.Unlock()
+	nowMilli := time.Now().UTC().UnixMilli()
+	if tidalHandler.tokenExpiryTimeMilli-nowMilli <= 5000 {
+		data := url.Values{}
+		data.Set("grant_type", "client_credentials")
+		req, err := http.NewRequest(http.MethodPost, "https://auth.tidal.com/v1/oauth2/token", strings.NewReader(data.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Add(
+			"Authorization",
+			fmt.Sprintf(
+				"Basic %s",
+				base64.StdEncoding.EncodeToString(
+					[]byte(
+						fmt.Sprintf(
+							"%s:%s",
+							clientId,
+							clientSecret,
+						),
+					),
+				),
+			),
+		)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		var tokenResponse TidalTokenData
+		err = json.Unmarshal(body, &tokenResponse)
+		if err != nil {
+			return "", err
+		}
+		if tokenResponse.AccessToken == "" {
+			return "", errors.New("no token returned from Tidal API")
+		}
+		tidalHandler.token = tokenResponse.AccessToken
+		tidalHandler.tokenExpiryTimeMilli = nowMilli + int64(tokenResponse.ExpiresIn)*1000
+	}
+	return tidalHandler.token, nil
+}
+
+// ValidateCredentials reports whether a token can currently be obtained with
+// the configured client credentials. Failing to reach the token endpoint is
+// not an authentication error, as it says nothing about the credentials.
+func (tidalHandler *TidalHandler) ValidateCredentials() error {
+	token, err := tidalHandler.getToken(tidalHandler.clientId, tidalHandler.clientSecret)
+	if _, ok := err.(*url.Error); ok {
+		return err
+	}
+	if token == "" || err != nil {
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	return nil
+}
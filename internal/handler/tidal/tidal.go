@@ -0,0 +1,211 @@
+package tidal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const apiUrlBase = "https://openapi.tidal.com/v2"
+const defaultCountryCode = "CA"
+const trackBatchSize = 20
+
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPattern(constants.PlaylistRequestType, `^https?://(?:www\.|listen\.)?tidal\.com/(?:browse/)?playlist/([\w-]+)`),
+	handler.NewUrlPattern(constants.AlbumRequestType, `^https?://(?:www\.|listen\.)?tidal\.com/(?:browse/)?album/(\d+)`),
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://(?:www\.|listen\.)?tidal\.com/(?:browse/)?track/(\d+)`),
+}
+
+func (tidalHandler *TidalHandler) Identifier() string {
+	return "tidal"
+}
+
+func (tidalHandler *TidalHandler) UrlPatterns() []handler.UrlPattern {
+	return urlPatterns
+}
+
+func (tidalHandler *TidalHandler) Track(request *http.Request) (trackInfo handler.TrackInfo, err error) {
+	vars := tidalHandler.pathParamsProvider(request)
+	trackParamValue := vars[constants.TrackIdentifierParam]
+	token, err := tidalHandler.getToken(tidalHandler.clientId, tidalHandler.clientSecret)
+	if token == "" || err != nil {
+		return handler.EmptyTrackInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	trackInfos, statusCode, err := tidalHandler.tracks([]string{trackParamValue}, nil, token)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(trackParamValue)
+	case http.StatusUnauthorized:
+		return handler.EmptyTrackInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	case http.StatusNotFound:
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	default:
+		return handler.EmptyTrackInfo, fmt.Errorf("tidal API returned status: %d", statusCode)
+	}
+	if len(trackInfos) == 0 {
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	}
+	return trackInfos[0], nil
+}
+
+func (tidalHandler *TidalHandler) Album(request *http.Request) (albumInfo handler.TrackCollectionInfo, err error) {
+	vars := tidalHandler.pathParamsProvider(request)
+	albumParamValue := vars[constants.AlbumIdentifierParam]
+	return tidalHandler.collection("albums", albumParamValue)
+}
+
+func (tidalHandler *TidalHandler) Playlist(request *http.Request) (playlistInfo handler.TrackCollectionInfo, err error) {
+	vars := tidalHandler.pathParamsProvider(request)
+	playlistParamValue := vars[constants.PlaylistIdentifierParam]
+	return tidalHandler.collection("playlists", playlistParamValue)
+}
+
+// Album and playlist item relationships only identify their tracks, so IDs
+// are collected page by page and then resolved in batches with their albums
+// and artists included. Album items also give each track's disc.
+func (tidalHandler *TidalHandler) collection(collectionType string, collectionId string) (handler.TrackCollectionInfo, error) {
+	token, err := tidalHandler.getToken(tidalHandler.clientId, tidalHandler.clientSecret)
+	if token == "" || err != nil {
+		return handler.EmptyTrackCollectionInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	nextUrl := fmt.Sprintf(
+		"%s/%s/%s/relationships/items?countryCode=%s",
+		apiUrlBase,
+		collectionType,
+		url.PathEscape(collectionId),
+		tidalHandler.countryCode,
+	)
+	trackIds := make([]string, 0)
+	discNumbers := make(map[string]int)
+	for nextUrl != "" {
+		var data TidalDocumentData
+		statusCode, err := getJson(nextUrl, token, &data)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		if err := collectionStatusError(statusCode, collectionId); err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		for _, entry := range data.Data {
+			if entry.Type == "tracks" {
+				trackIds = append(trackIds, entry.ID)
+				discNumbers[entry.ID] = entry.Meta.VolumeNumber
+			}
+		}
+		nextUrl = ""
+		if data.Links.Next != "" {
+			nextUrl = fmt.Sprintf("%s%s", apiUrlBase, data.Links.Next)
+		}
+	}
+	trackInfos := make([]handler.TrackInfo, 0, len(trackIds))
+	for start := 0; start < len(trackIds); start += trackBatchSize {
+		batch, statusCode, err := tidalHandler.tracks(trackIds[start:min(start+trackBatchSize, len(trackIds))], discNumbers, token)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		if err := collectionStatusError(statusCode, collectionId); err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		trackInfos = append(trackInfos, batch...)
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, collectionId), nil
+}
+
+func (tidalHandler *TidalHandler) tracks(trackIds []string, discNumbers map[string]int, token string) ([]handler.TrackInfo, int, error) {
+	query := url.Values{}
+	query.Set("countryCode", tidalHandler.countryCode)
+	query.Set("filter[id]", strings.Join(trackIds, ","))
+	query.Set("include", "albums,artists")
+	var data TidalDocumentData
+	statusCode, err := getJson(fmt.Sprintf("%s/tracks?%s", apiUrlBase, query.Encode()), token, &data)
+	if err != nil || statusCode != http.StatusOK {
+		return nil, statusCode, err
+	}
+	included := make(map[string]TidalResourceData)
+	for _, resource := range data.Included {
+		included[fmt.Sprintf("%s/%s", resource.Type, resource.ID)] = resource
+	}
+	tracksById := make(map[string]handler.TrackInfo)
+	for _, track := range data.Data {
+		tracksById[track.ID] = tidalHandler.trackInfoFromTidalResourceData(track, included)
+	}
+	// filter[id] does not guarantee response order, so restore the requested order
+	trackInfos := make([]handler.TrackInfo, 0, len(trackIds))
+	for _, trackId := range trackIds {
+		if trackInfo, ok := tracksById[trackId]; ok {
+			trackInfo.DiscNumber = discNumbers[trackId]
+			trackInfos = append(trackInfos, trackInfo)
+		}
+	}
+	return trackInfos, statusCode, nil
+}
+
+func (tidalHandler *TidalHandler) trackInfoFromTidalResourceData(track TidalResourceData, included map[string]TidalResourceData) handler.TrackInfo {
+	artistNames := make([]string, 0, len(track.Relationships.Artists.Data))
+	for _, artist := range track.Relationships.Artists.Data {
+		if resource, ok := included[fmt.Sprintf("artists/%s", artist.ID)]; ok {
+			artistNames = append(artistNames, resource.Attributes.Name)
+		}
+	}
+	var album TidalResourceData
+	if len(track.Relationships.Albums.Data) > 0 {
+		album = included[fmt.Sprintf("albums/%s", track.Relationships.Albums.Data[0].ID)]
+	}
+	trackInfo := handler.NewTrackInfo(
+		strings.Join(artistNames, ", "),
+		track.Attributes.Title,
+		album.Attributes.Title,
+		album.Attributes.AlbumType == "SINGLE",
+		false,
+	)
+	trackInfo = tidalHandler.newReleaseRules.Apply(trackInfo, album.Attributes.ReleaseDate, handler.ReleaseDatePrecision(album.Attributes.ReleaseDate))
+	trackInfo.Duration = handler.SecondsFromIsoDuration(track.Attributes.Duration)
+	return trackInfo
+}
+
+func collectionStatusError(statusCode int, collectionId string) error {
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusBadRequest:
+		return handler.NewInvalidTrackCollectionIdError(collectionId)
+	case http.StatusUnauthorized:
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	case http.StatusNotFound:
+		return handler.NewTrackCollectionNotFoundError(collectionId)
+	default:
+		return fmt.Errorf("tidal API returned status: %d", statusCode)
+	}
+}
+
+func getJson(url string, token string, target any) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, handler.NewInternalError(err.Error())
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Add("Accept", "application/vnd.api+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, json.Unmarshal(body, target)
+}
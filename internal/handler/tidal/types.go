@@ -0,0 +1,78 @@
+package tidal
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type TidalResourceIdentifierData struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type TidalResourceData struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Title       string `json:"title"`
+		Name        string `json:"name"`
+		ReleaseDate string `json:"releaseDate"`
+		AlbumType   string `json:"type"`
+		Duration    string `json:"duration"`
+	} `json:"attributes"`
+	Relationships struct {
+		Artists struct {
+			Data []TidalResourceIdentifierData `json:"data"`
+		} `json:"artists"`
+		Albums struct {
+			Data []TidalResourceIdentifierData `json:"data"`
+		} `json:"albums"`
+	} `json:"relationships"`
+	// Meta is only set on album items, where it places the track on the album
+	Meta struct {
+		VolumeNumber int `json:"volumeNumber"`
+	} `json:"meta"`
+}
+
+type TidalDocumentData struct {
+	Data     []TidalResourceData `json:"data"`
+	Included []TidalResourceData `json:"included"`
+	Links    struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+type TidalHandler struct {
+	clientId             string
+	clientSecret         string
+	countryCode          string
+	token                string
+	tokenExpiryTimeMilli int64
+	tokenMutex           sync.Mutex
+	pathParamsProvider   func(*http.Request) map[string]string
+	newReleaseRules      *newrelease.Rules
+}
+
+func NewTidalHandler(
+	clientId string,
+	clientSecret string,
+	countryCode string,
+	newReleaseRules *newrelease.Rules,
+) *TidalHandler {
+	if countryCode == "" {
+		countryCode = defaultCountryCode
+	}
+	return &TidalHandler{
+		clientId:             clientId,
+		clientSecret:         clientSecret,
+		countryCode:          countryCode,
+		token:                "",
+		tokenExpiryTimeMilli: 0,
+		pathParamsProvider:   mux.Vars,
+		newReleaseRules:      newReleaseRules,
+	}
+}
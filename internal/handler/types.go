@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"time"
 )
 
 type TrackInfoHandler interface {
@@ -20,14 +21,72 @@ type TrackInfoTrackHandler interface {
 	Track(*http.Request) (TrackInfo, error)
 }
 
+type TrackInfoEpisodeHandler interface {
+	Episode(*http.Request) (TrackInfo, error)
+}
+
+type TrackInfoShowHandler interface {
+	Show(*http.Request) (TrackCollectionInfo, error)
+}
+
+type TrackInfoUploadHandler interface {
+	Upload(*http.Request) (TrackCollectionInfo, error)
+}
+
+// TrackInfoUrlHandler is implemented by handlers that can recognise links to
+// their provider, so that a pasted link can be resolved without the client
+// knowing which provider it belongs to.
+type TrackInfoUrlHandler interface {
+	UrlPatterns() []UrlPattern
+}
+
+// TrackInfoCredentialsHandler is implemented by handlers that need
+// credentials, to report whether those configured are currently accepted.
+type TrackInfoCredentialsHandler interface {
+	ValidateCredentials() error
+}
+
 type TrackInfo struct {
-	Artist   string `json:"artist"`
-	Track    string `json:"track"`
-	IsSingle bool   `json:"isSingle"`
-	Album    string `json:"album"`
-	IsNew    bool   `json:"isNew"`
+	Artist        string     `json:"artist"`
+	Track         string     `json:"track"`
+	IsSingle      bool       `json:"isSingle"`
+	Album         string     `json:"album"`
+	IsNew         bool       `json:"isNew"`
+	NewReason     string     `json:"newReason,omitempty"`
+	ReleaseDate   string     `json:"releaseDate,omitempty"`
+	Label         string     `json:"label,omitempty"`
+	Link          string     `json:"link,omitempty"`
+	LowConfidence bool       `json:"lowConfidence,omitempty"`
+	Confidence    *float64   `json:"confidence,omitempty"`
+	LookupError   string     `json:"lookupError,omitempty"`
+	StartTime     *time.Time `json:"startTime,omitempty"`
+	DiscNumber    int        `json:"discNumber,omitempty"`
+	AddedAt       *time.Time `json:"addedAt,omitempty"`
+	// Duration is in seconds, and 0 where the source does not record it
+	Duration           int        `json:"duration,omitempty"`
+	EstimatedStartTime *time.Time `json:"estimatedStartTime,omitempty"`
+	Overruns           bool       `json:"overruns,omitempty"`
+	IsInstrumental     string     `json:"isInstrumental,omitempty"`
+	// CanCon is only assessed on request, as it may need a lookup per artist
+	CanCon        string   `json:"canCon,omitempty"`
+	CanConReasons []string `json:"canConReasons,omitempty"`
+	// RowType is empty for music, which is every row a provider returns
+	RowType string `json:"rowType,omitempty"`
 }
 
+// Assessments that cannot always be made answer unknown rather than guess.
+const (
+	AssessmentTrue    = "true"
+	AssessmentFalse   = "false"
+	AssessmentUnknown = "unknown"
+)
+
+// Non-music rows inserted between tracks for the station's log.
+const (
+	RowTypeStationId = "stationId"
+	RowTypePSA       = "psa"
+)
+
 func NewTrackInfo(artist, track, album string, isSingle, isNew bool) TrackInfo {
 	return TrackInfo{
 		Artist:   artist,
@@ -40,9 +99,28 @@ func NewTrackInfo(artist, track, album string, isSingle, isNew bool) TrackInfo {
 
 var EmptyTrackInfo = TrackInfo{}
 
+type ReleaseInfo struct {
+	Label           string `json:"label"`
+	CatalogueNumber string `json:"catalogueNumber"`
+	Year            int    `json:"year"`
+}
+
+// ScheduleInfo compares a collection's runtime with the show's slot. Times
+// are in seconds; Remaining is negative when the tracks overrun the slot.
+type ScheduleInfo struct {
+	ShowStart        time.Time `json:"showStart"`
+	SlotLength       int       `json:"slotLength"`
+	Runtime          int       `json:"runtime"`
+	Remaining        int       `json:"remaining"`
+	Overruns         bool      `json:"overruns"`
+	UnknownDurations int       `json:"unknownDurations"`
+}
+
 type TrackCollectionInfo struct {
-	Tracks       []TrackInfo `json:"tracks"`
-	CollectionId string      `json:"collectionId"`
+	Tracks       []TrackInfo   `json:"tracks"`
+	CollectionId string        `json:"collectionId"`
+	Release      *ReleaseInfo  `json:"release,omitempty"`
+	Schedule     *ScheduleInfo `json:"schedule,omitempty"`
 }
 
 func NewTrackCollectionInfo(tracks []TrackInfo, collectionId string) TrackCollectionInfo {
@@ -56,3 +134,52 @@ var EmptyTrackCollectionInfo = TrackCollectionInfo{
 	Tracks:       []TrackInfo{},
 	CollectionId: "",
 }
+
+// ResolvedInfo is the result of resolving a link, identifying which provider
+// and request type handled it. Single tracks and episodes are returned as a
+// collection of one.
+type ResolvedInfo struct {
+	Provider string `json:"provider"`
+	Type     string `json:"type"`
+	TrackCollectionInfo
+}
+
+const (
+	CredentialsValid       = "valid"
+	CredentialsInvalid     = "invalid"
+	CredentialsNotRequired = "notRequired"
+	CredentialsUnknown     = "unknown"
+)
+
+type UrlPatternInfo struct {
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
+}
+
+// ProviderInfo describes a registered handler: what it can fetch, which links
+// it recognises and whether it is currently usable.
+type ProviderInfo struct {
+	Identifier   string           `json:"identifier"`
+	Capabilities []string         `json:"capabilities"`
+	Icon         string           `json:"icon,omitempty"`
+	UrlPatterns  []UrlPatternInfo `json:"urlPatterns"`
+	Credentials  string           `json:"credentials"`
+}
+
+// BatchItemInfo reports the outcome of one item in a batch. Status is the
+// HTTP status the item would have had as a request of its own.
+type BatchItemInfo struct {
+	Item       string `json:"item"`
+	Provider   string `json:"provider,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Status     int    `json:"status"`
+	Error      string `json:"error,omitempty"`
+	TrackCount int    `json:"trackCount"`
+}
+
+// BatchInfo is the tracks of every successful item in a batch, in item order,
+// with the outcome of each item.
+type BatchInfo struct {
+	TrackCollectionInfo
+	Items []BatchItemInfo `json:"items"`
+}
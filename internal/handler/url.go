@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+)
+
+// UrlPattern recognises links to one type of resource at a provider. The
+// pattern's capture groups, joined with "/", are the identifier that the
+// handler expects for that request type. Links whose identifier contains an
+// excluded path segment, such as a profile's "likes" page, are not matched.
+type UrlPattern struct {
+	RequestType      constants.RequestType
+	Pattern          *regexp.Regexp
+	ExcludedSegments []string
+}
+
+func NewUrlPattern(requestType constants.RequestType, pattern string) UrlPattern {
+	return UrlPattern{
+		RequestType: requestType,
+		Pattern:     regexp.MustCompile(pattern),
+	}
+}
+
+// NewUrlPatternExcluding is for patterns that capture path segments which the
+// provider also uses for its own pages.
+func NewUrlPatternExcluding(requestType constants.RequestType, pattern string, excludedSegments ...string) UrlPattern {
+	urlPattern := NewUrlPattern(requestType, pattern)
+	urlPattern.ExcludedSegments = excludedSegments
+	return urlPattern
+}
+
+func (urlPattern UrlPattern) Match(link string) (string, bool) {
+	matches := urlPattern.Pattern.FindStringSubmatch(link)
+	if matches == nil {
+		return "", false
+	}
+	identifier := strings.Join(matches[1:], "/")
+	for _, segment := range strings.Split(identifier, "/") {
+		if slices.Contains(urlPattern.ExcludedSegments, strings.ToLower(segment)) {
+			return "", false
+		}
+	}
+	return identifier, true
+}
@@ -0,0 +1,90 @@
+package youtube
+
+import (
+	"regexp"
+	"strings"
+)
+
+const topicChannelSuffix = " - Topic"
+const musicMetadataSeparator = " · "
+const releasedOnPrefix = "Released on: "
+
+var titleSeparators = []string{" - ", " – ", " — ", " -- ", " | "}
+
+var titleDecorationRegex = regexp.MustCompile(`(?i)\s*[\(\[][^\)\]]*\b(official|video|audio|lyrics?|visuali[sz]er|hd|hq|4k|remaster(ed)?)\b[^\)\]]*[\)\]]`)
+var channelDecorationRegex = regexp.MustCompile(`(?i)(vevo|\s+official)$`)
+var releasedOnRegex = regexp.MustCompile(`(?m)^` + releasedOnPrefix + `(\d{4}-\d{2}-\d{2})\s*$`)
+
+// Videos auto-generated by YouTube Music for "Topic" channels carry a
+// structured description:
+//
+//	Provided to YouTube by {distributor}
+//
+//	{track} · {artist} · {artist}
+//
+//	{album}
+//
+//	℗ ...
+//
+//	Released on: {YYYY-MM-DD}
+func parseMusicMetadata(snippet YouTubeSnippetData) (youTubeMusicMetadata, bool) {
+	if !strings.HasSuffix(ownerChannelTitle(snippet), topicChannelSuffix) {
+		return youTubeMusicMetadata{}, false
+	}
+	paragraphs := make([]string, 0)
+	for _, paragraph := range strings.Split(strings.ReplaceAll(snippet.Description, "\r\n", "\n"), "\n\n") {
+		if trimmed := strings.TrimSpace(paragraph); trimmed != "" {
+			paragraphs = append(paragraphs, trimmed)
+		}
+	}
+	for i, paragraph := range paragraphs {
+		parts := strings.Split(paragraph, musicMetadataSeparator)
+		if len(parts) < 2 || strings.Contains(paragraph, "\n") {
+			continue
+		}
+		metadata := youTubeMusicMetadata{
+			track:  parts[0],
+			artist: strings.Join(parts[1:], ", "),
+		}
+		if i+1 < len(paragraphs) && !strings.HasPrefix(paragraphs[i+1], "℗") && !strings.HasPrefix(paragraphs[i+1], releasedOnPrefix) {
+			metadata.album = paragraphs[i+1]
+		}
+		if match := releasedOnRegex.FindStringSubmatch(snippet.Description); match != nil {
+			metadata.releaseDate = match[1]
+		}
+		return metadata, true
+	}
+	return youTubeMusicMetadata{}, false
+}
+
+// Most music uploads follow an "Artist - Title" convention, but nothing
+// enforces it, so ok is false whenever the split is ambiguous.
+func splitVideoTitle(title string) (artist string, track string, ok bool) {
+	cleaned := strings.TrimSpace(titleDecorationRegex.ReplaceAllString(title, ""))
+	for _, separator := range titleSeparators {
+		parts := strings.Split(cleaned, separator)
+		if len(parts) == 2 {
+			artist = strings.TrimSpace(parts[0])
+			track = strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+			if artist != "" && track != "" {
+				return artist, track, true
+			}
+		}
+		if len(parts) > 2 {
+			return "", cleaned, false
+		}
+	}
+	return "", cleaned, false
+}
+
+func ownerChannelTitle(snippet YouTubeSnippetData) string {
+	if snippet.VideoOwnerChannelTitle != "" {
+		return snippet.VideoOwnerChannelTitle
+	}
+	return snippet.ChannelTitle
+}
+
+func artistFromChannelTitle(channelTitle string) string {
+	artist := strings.TrimSuffix(channelTitle, topicChannelSuffix)
+	return strings.TrimSpace(channelDecorationRegex.ReplaceAllString(artist, ""))
+}
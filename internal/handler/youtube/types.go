@@ -0,0 +1,63 @@
+package youtube
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
+
+	"github.com/gorilla/mux"
+)
+
+type YouTubeSnippetData struct {
+	Title                  string `json:"title"`
+	Description            string `json:"description"`
+	ChannelTitle           string `json:"channelTitle"`
+	VideoOwnerChannelTitle string `json:"videoOwnerChannelTitle"`
+	// for playlist items this is when the video was added to the playlist
+	PublishedAt *time.Time `json:"publishedAt"`
+}
+
+type YouTubeVideosData struct {
+	Items []struct {
+		ID             string             `json:"id"`
+		Snippet        YouTubeSnippetData `json:"snippet"`
+		ContentDetails struct {
+			Duration string `json:"duration"`
+		} `json:"contentDetails"`
+	} `json:"items"`
+}
+
+type YouTubePlaylistItemsData struct {
+	NextPageToken string `json:"nextPageToken"`
+	Items         []struct {
+		Snippet        YouTubeSnippetData `json:"snippet"`
+		ContentDetails struct {
+			VideoID string `json:"videoId"`
+		} `json:"contentDetails"`
+	} `json:"items"`
+}
+
+type youTubeMusicMetadata struct {
+	artist      string
+	track       string
+	album       string
+	releaseDate string
+}
+
+type YouTubeHandler struct {
+	apiKey             string
+	pathParamsProvider func(*http.Request) map[string]string
+	newReleaseRules    *newrelease.Rules
+}
+
+func NewYouTubeHandler(
+	apiKey string,
+	newReleaseRules *newrelease.Rules,
+) *YouTubeHandler {
+	return &YouTubeHandler{
+		apiKey:             apiKey,
+		pathParamsProvider: mux.Vars,
+		newReleaseRules:    newReleaseRules,
+	}
+}
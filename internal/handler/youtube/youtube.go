@@ -0,0 +1,213 @@
+package youtube
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const apiUrlBase = "https://www.googleapis.com/youtube/v3"
+
+// A video played from within a playlist links to both; the video is what was
+// shared, so the track patterns are matched first.
+var urlPatterns = []handler.UrlPattern{
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://(?:www\.|m\.|music\.)?youtube\.com/watch\?(?:[^#]*&)?v=([\w-]{11})`),
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://(?:www\.|m\.)?youtube\.com/(?:shorts|live|embed)/([\w-]{11})`),
+	handler.NewUrlPattern(constants.TrackRequestType, `^https?://youtu\.be/([\w-]{11})`),
+	handler.NewUrlPattern(constants.PlaylistRequestType, `^https?://(?:www\.|m\.|music\.)?youtube\.com/playlist\?(?:[^#]*&)?list=([\w-]+)`),
+}
+
+func (youTubeHandler *YouTubeHandler) Identifier() string {
+	return "youtube"
+}
+
+func (youTubeHandler *YouTubeHandler) UrlPatterns() []handler.UrlPattern {
+	return urlPatterns
+}
+
+// ValidateCredentials makes the cheapest request the Data API offers, as API
+// keys can only be checked by using them.
+func (youTubeHandler *YouTubeHandler) ValidateCredentials() error {
+	if youTubeHandler.apiKey == "" {
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	query := url.Values{}
+	query.Set("part", "id")
+	query.Set("key", youTubeHandler.apiKey)
+	var data any
+	statusCode, err := getJson(fmt.Sprintf("%s/i18nRegions?%s", apiUrlBase, query.Encode()), &data)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusOK {
+		return handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	return nil
+}
+
+func (youTubeHandler *YouTubeHandler) Track(request *http.Request) (trackInfo handler.TrackInfo, err error) {
+	vars := youTubeHandler.pathParamsProvider(request)
+	trackParamValue := vars[constants.TrackIdentifierParam]
+	if youTubeHandler.apiKey == "" {
+		return handler.EmptyTrackInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	query := url.Values{}
+	query.Set("part", "snippet,contentDetails")
+	query.Set("id", trackParamValue)
+	query.Set("key", youTubeHandler.apiKey)
+	var data YouTubeVideosData
+	statusCode, err := getJson(fmt.Sprintf("%s/videos?%s", apiUrlBase, query.Encode()), &data)
+	if err != nil {
+		return handler.EmptyTrackInfo, err
+	}
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return handler.EmptyTrackInfo, handler.NewInvalidTrackIdError(trackParamValue)
+	case http.StatusForbidden:
+		return handler.EmptyTrackInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	case http.StatusNotFound:
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	default:
+		return handler.EmptyTrackInfo, fmt.Errorf("youtube API returned status: %d", statusCode)
+	}
+	if len(data.Items) == 0 {
+		return handler.EmptyTrackInfo, handler.NewTrackNotFoundError(trackParamValue)
+	}
+	trackInfo = youTubeHandler.trackInfoFromYouTubeSnippetData(data.Items[0].Snippet)
+	trackInfo.Link = videoLink(trackParamValue)
+	trackInfo.Duration = handler.SecondsFromIsoDuration(data.Items[0].ContentDetails.Duration)
+	return trackInfo, nil
+}
+
+func (youTubeHandler *YouTubeHandler) Playlist(request *http.Request) (playlistInfo handler.TrackCollectionInfo, err error) {
+	vars := youTubeHandler.pathParamsProvider(request)
+	playlistParamValue := vars[constants.PlaylistIdentifierParam]
+	if youTubeHandler.apiKey == "" {
+		return handler.EmptyTrackCollectionInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+	}
+	trackInfos := make([]handler.TrackInfo, 0)
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("part", "snippet,contentDetails")
+		query.Set("maxResults", "50")
+		query.Set("playlistId", playlistParamValue)
+		query.Set("key", youTubeHandler.apiKey)
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var data YouTubePlaylistItemsData
+		statusCode, err := getJson(fmt.Sprintf("%s/playlistItems?%s", apiUrlBase, query.Encode()), &data)
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		switch statusCode {
+		case http.StatusOK:
+		case http.StatusBadRequest:
+			return handler.EmptyTrackCollectionInfo, handler.NewInvalidTrackCollectionIdError(playlistParamValue)
+		case http.StatusForbidden:
+			return handler.EmptyTrackCollectionInfo, handler.NewHandlerAuthenticationError(handler.ApplicationCredentials)
+		case http.StatusNotFound:
+			return handler.EmptyTrackCollectionInfo, handler.NewTrackCollectionNotFoundError(playlistParamValue)
+		default:
+			return handler.EmptyTrackCollectionInfo, fmt.Errorf("youtube API returned status: %d", statusCode)
+		}
+		videoIds := make([]string, 0, len(data.Items))
+		for _, entry := range data.Items {
+			videoIds = append(videoIds, entry.ContentDetails.VideoID)
+		}
+		durations := youTubeHandler.videoDurations(videoIds)
+		for _, entry := range data.Items {
+			trackInfo := youTubeHandler.trackInfoFromYouTubeSnippetData(entry.Snippet)
+			trackInfo.AddedAt = entry.Snippet.PublishedAt
+			trackInfo.Link = videoLink(entry.ContentDetails.VideoID)
+			trackInfo.Duration = durations[entry.ContentDetails.VideoID]
+			trackInfos = append(trackInfos, trackInfo)
+		}
+		if data.NextPageToken == "" {
+			break
+		}
+		pageToken = data.NextPageToken
+	}
+	return handler.NewTrackCollectionInfo(trackInfos, playlistParamValue), nil
+}
+
+// Playlist items do not carry video durations, so they are read with one
+// videos request per page. Durations are informational; a failed lookup
+// leaves them unknown rather than failing the playlist.
+func (youTubeHandler *YouTubeHandler) videoDurations(videoIds []string) map[string]int {
+	durations := make(map[string]int)
+	if len(videoIds) == 0 {
+		return durations
+	}
+	query := url.Values{}
+	query.Set("part", "contentDetails")
+	query.Set("id", strings.Join(videoIds, ","))
+	query.Set("key", youTubeHandler.apiKey)
+	var data YouTubeVideosData
+	statusCode, err := getJson(fmt.Sprintf("%s/videos?%s", apiUrlBase, query.Encode()), &data)
+	if err != nil || statusCode != http.StatusOK {
+		return durations
+	}
+	for _, item := range data.Items {
+		durations[item.ID] = handler.SecondsFromIsoDuration(item.ContentDetails.Duration)
+	}
+	return durations
+}
+
+func (youTubeHandler *YouTubeHandler) trackInfoFromYouTubeSnippetData(snippet YouTubeSnippetData) handler.TrackInfo {
+	if metadata, ok := parseMusicMetadata(snippet); ok {
+		trackInfo := handler.NewTrackInfo(
+			metadata.artist,
+			metadata.track,
+			metadata.album,
+			metadata.album == "" || metadata.album == metadata.track,
+			false,
+		)
+		return youTubeHandler.newReleaseRules.Apply(trackInfo, metadata.releaseDate, handler.ReleaseDatePrecisionDay)
+	}
+	artist, track, ok := splitVideoTitle(snippet.Title)
+	if !ok {
+		artist = artistFromChannelTitle(ownerChannelTitle(snippet))
+	}
+	trackInfo := handler.NewTrackInfo(
+		artist,
+		track,
+		"",
+		false,
+		false,
+	)
+	trackInfo.LowConfidence = !ok
+	return youTubeHandler.newReleaseRules.Apply(trackInfo, "", "")
+}
+
+func videoLink(videoId string) string {
+	return fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoId)
+}
+
+func getJson(url string, target any) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, handler.NewInternalError(err.Error())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, json.Unmarshal(body, target)
+}
@@ -0,0 +1,230 @@
+package insert
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const (
+	EntryParam        = "insert"
+	EveryTracksParam  = "insertEveryTracks"
+	EveryMinutesParam = "insertEveryMinutes"
+	PositionsParam    = "insertAt"
+	TemplateParam     = "insertTemplate"
+)
+
+var Params = []string{EntryParam, EveryTracksParam, EveryMinutesParam, PositionsParam, TemplateParam}
+
+// DefaultEntryName is the entry inserted when a request does not name one. A
+// station can configure its own entry by this name to replace the default.
+const DefaultEntryName = "stationId"
+
+var defaultEntry = Entry{
+	Type:  handler.RowTypeStationId,
+	Title: "Station ID",
+}
+
+// Entry is a non-music row for the station's log, such as a station ID or a
+// PSA. Duration is in seconds and counts towards the estimated runtime.
+type Entry struct {
+	Type     string `json:"type"`
+	Artist   string `json:"artist"`
+	Title    string `json:"title"`
+	Duration int    `json:"duration"`
+}
+
+// Rule places an entry every so many tracks, every so many minutes of
+// estimated runtime, before given track positions, or any combination of
+// these. Positions are 1-based and count tracks only; one past the last
+// track places the entry at the end.
+type Rule struct {
+	Entry        string `json:"entry"`
+	EveryTracks  int    `json:"everyTracks"`
+	EveryMinutes int    `json:"everyMinutes"`
+	Positions    []int  `json:"positions"`
+}
+
+func (rule Rule) IsEmpty() bool {
+	return rule.EveryTracks == 0 && rule.EveryMinutes == 0 && len(rule.Positions) == 0
+}
+
+// Inserter holds the station's entries and show templates, which are named
+// lists of rules so that a show's usual breaks need not be given on every
+// request.
+type Inserter struct {
+	entries   map[string]Entry
+	templates map[string][]Rule
+}
+
+func NewInserter(entries map[string]Entry, templates map[string][]Rule) *Inserter {
+	inserter := &Inserter{
+		entries: map[string]Entry{
+			DefaultEntryName: defaultEntry,
+		},
+		templates: make(map[string][]Rule, len(templates)),
+	}
+	for name, entry := range entries {
+		inserter.entries[name] = entry
+	}
+	for name, rules := range templates {
+		inserter.templates[name] = rules
+	}
+	return inserter
+}
+
+// ValidRowType reports whether rowType is a type of non-music row.
+func ValidRowType(rowType string) bool {
+	return rowType == handler.RowTypeStationId || rowType == handler.RowTypePSA
+}
+
+// Insertion is the set of rules a request asks for, with their entries
+// resolved.
+type Insertion struct {
+	rules   []Rule
+	entries []Entry
+}
+
+func (insertion Insertion) IsEmpty() bool {
+	return len(insertion.rules) == 0
+}
+
+// NewInsertion reads either a show template or a single rule from the
+// request. A rule inserts the default station ID unless it names an entry.
+func (inserter *Inserter) NewInsertion(query url.Values) (Insertion, error) {
+	var insertion Insertion
+	rule := Rule{Entry: DefaultEntryName}
+	if value := query.Get(EntryParam); value != "" {
+		rule.Entry = value
+	}
+	var err error
+	if rule.EveryTracks, err = handler.PositiveIntParam(query, EveryTracksParam); err != nil {
+		return insertion, err
+	}
+	if rule.EveryMinutes, err = handler.PositiveIntParam(query, EveryMinutesParam); err != nil {
+		return insertion, err
+	}
+	if value := query.Get(PositionsParam); value != "" {
+		if rule.Positions, err = parsePositions(value); err != nil {
+			return insertion, err
+		}
+	}
+	if templateName := query.Get(TemplateParam); templateName != "" {
+		if !rule.IsEmpty() || query.Get(EntryParam) != "" {
+			return insertion, handler.NewInvalidRequestError(fmt.Sprintf("'%s' cannot be combined with other insert parameters", TemplateParam))
+		}
+		rules, ok := inserter.templates[templateName]
+		if !ok {
+			return insertion, handler.NewInvalidRequestError(fmt.Sprintf("unknown '%s': %s", TemplateParam, templateName))
+		}
+		return inserter.resolve(rules)
+	}
+	if rule.IsEmpty() {
+		if query.Get(EntryParam) != "" {
+			return insertion, handler.NewInvalidRequestError(fmt.Sprintf("'%s' requires '%s', '%s' or '%s'", EntryParam, EveryTracksParam, EveryMinutesParam, PositionsParam))
+		}
+		return insertion, nil
+	}
+	return inserter.resolve([]Rule{rule})
+}
+
+func (inserter *Inserter) resolve(rules []Rule) (Insertion, error) {
+	insertion := Insertion{
+		rules:   rules,
+		entries: make([]Entry, len(rules)),
+	}
+	for i, rule := range rules {
+		entry, ok := inserter.entries[rule.Entry]
+		if !ok {
+			return Insertion{}, handler.NewInvalidRequestError(fmt.Sprintf("unknown '%s': %s", EntryParam, rule.Entry))
+		}
+		insertion.entries[i] = entry
+	}
+	return insertion, nil
+}
+
+// Apply inserts the entries between the tracks. Periodic entries only go
+// between tracks, never before the first or after the last, and a rule adds
+// at most one entry at any point; a track that runs past more than one
+// minute mark is followed by a single entry. Rules are applied in order where
+// several fall at the same point.
+func (insertion Insertion) Apply(tracks []handler.TrackInfo) []handler.TrackInfo {
+	if insertion.IsEmpty() {
+		return tracks
+	}
+	inserted := make([]handler.TrackInfo, 0, len(tracks))
+	nextMarks := make([]int, len(insertion.rules))
+	for i, rule := range insertion.rules {
+		nextMarks[i] = rule.EveryMinutes * 60
+	}
+	elapsed := 0
+	for position := 1; position <= len(tracks)+1; position++ {
+		between := position > 1 && position <= len(tracks)
+		for i, rule := range insertion.rules {
+			due := slices.Contains(rule.Positions, position)
+			if between && rule.EveryTracks > 0 && (position-1)%rule.EveryTracks == 0 {
+				due = true
+			}
+			if between && rule.EveryMinutes > 0 && elapsed >= nextMarks[i] {
+				for nextMarks[i] <= elapsed {
+					nextMarks[i] += rule.EveryMinutes * 60
+				}
+				due = true
+			}
+			if due {
+				inserted = append(inserted, insertion.entries[i].row())
+				elapsed += insertion.entries[i].Duration
+			}
+		}
+		if position <= len(tracks) {
+			inserted = append(inserted, tracks[position-1])
+			elapsed += tracks[position-1].Duration
+		}
+	}
+	return inserted
+}
+
+func (entry Entry) row() handler.TrackInfo {
+	return handler.TrackInfo{
+		Artist:   entry.Artist,
+		Track:    entry.Title,
+		Duration: entry.Duration,
+		RowType:  entry.Type,
+	}
+}
+
+// WithoutParams removes the insert parameters from a query, for requests
+// whose results are combined before entries are inserted.
+func WithoutParams(query url.Values) url.Values {
+	filtered := make(url.Values, len(query))
+	for name, values := range query {
+		if !slices.Contains(Params, name) {
+			filtered[name] = values
+		}
+	}
+	return filtered
+}
+
+// Positions are a comma separated list, e.g. "1,5,9".
+func parsePositions(value string) ([]int, error) {
+	positions := make([]int, 0)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		position, err := strconv.Atoi(part)
+		if err != nil || position < 1 {
+			return nil, handler.NewInvalidRequestError(fmt.Sprintf("invalid '%s': %s", PositionsParam, value))
+		}
+		positions = append(positions, position)
+	}
+	if len(positions) == 0 {
+		return nil, handler.NewInvalidRequestError(fmt.Sprintf("invalid '%s': %s", PositionsParam, value))
+	}
+	return positions, nil
+}
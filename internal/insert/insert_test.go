@@ -0,0 +1,126 @@
+package insert
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+var testInserter = NewInserter(
+	map[string]Entry{
+		"psa": {Type: handler.RowTypePSA, Title: "PSA", Duration: 30},
+	},
+	map[string][]Rule{
+		"breaks": {
+			{Entry: "psa", EveryTracks: 2},
+			{Entry: DefaultEntryName, Positions: []int{3}},
+		},
+	},
+)
+
+func testTracks(durations ...int) []handler.TrackInfo {
+	tracks := make([]handler.TrackInfo, len(durations))
+	for i, duration := range durations {
+		tracks[i] = handler.TrackInfo{Track: fmt.Sprintf("T%d", i+1), Duration: duration}
+	}
+	return tracks
+}
+
+// Rows are compared by title, which is enough to tell tracks and entries apart.
+func titles(rows []handler.TrackInfo) []string {
+	titles := make([]string, len(rows))
+	for i, row := range rows {
+		titles[i] = row.Track
+	}
+	return titles
+}
+
+func TestInsertionApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  url.Values
+		tracks []handler.TrackInfo
+		want   []string
+	}{
+		{
+			name:   "no insertion",
+			query:  url.Values{},
+			tracks: testTracks(240, 240),
+			want:   []string{"T1", "T2"},
+		},
+		{
+			name:   "every 3 tracks",
+			query:  url.Values{EveryTracksParam: {"3"}},
+			tracks: testTracks(240, 240, 240, 240, 240, 240, 240),
+			want:   []string{"T1", "T2", "T3", "Station ID", "T4", "T5", "T6", "Station ID", "T7"},
+		},
+		{
+			name:   "never after the last track",
+			query:  url.Values{EveryTracksParam: {"3"}},
+			tracks: testTracks(240, 240, 240, 240, 240, 240),
+			want:   []string{"T1", "T2", "T3", "Station ID", "T4", "T5", "T6"},
+		},
+		{
+			name:   "every 10 minutes counting entry durations",
+			query:  url.Values{EntryParam: {"psa"}, EveryMinutesParam: {"10"}},
+			tracks: testTracks(240, 240, 240, 240, 240, 240),
+			want:   []string{"T1", "T2", "T3", "PSA", "T4", "T5", "PSA", "T6"},
+		},
+		{
+			name:   "one entry for a track past several marks",
+			query:  url.Values{EveryMinutesParam: {"5"}},
+			tracks: testTracks(720, 60, 60, 60),
+			want:   []string{"T1", "Station ID", "T2", "T3", "T4"},
+		},
+		{
+			name:   "positions including the start and end",
+			query:  url.Values{PositionsParam: {"1,3,5"}},
+			tracks: testTracks(240, 240, 240, 240),
+			want:   []string{"Station ID", "T1", "T2", "Station ID", "T3", "T4", "Station ID"},
+		},
+		{
+			name:   "template rules in order at the same point",
+			query:  url.Values{TemplateParam: {"breaks"}},
+			tracks: testTracks(240, 240, 240, 240),
+			want:   []string{"T1", "T2", "PSA", "Station ID", "T3", "T4"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			insertion, err := testInserter.NewInsertion(test.query)
+			if err != nil {
+				t.Fatalf("NewInsertion: %v", err)
+			}
+			got := titles(insertion.Apply(test.tracks))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Apply:\n got %v\nwant %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewInsertionInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"zero tracks", url.Values{EveryTracksParam: {"0"}}},
+		{"fractional minutes", url.Values{EveryMinutesParam: {"7.5"}}},
+		{"position zero", url.Values{PositionsParam: {"0,4"}}},
+		{"unknown entry", url.Values{EntryParam: {"jingle"}, EveryTracksParam: {"3"}}},
+		{"entry without placement", url.Values{EntryParam: {"psa"}}},
+		{"unknown template", url.Values{TemplateParam: {"weekend"}}},
+		{"template with other parameters", url.Values{TemplateParam: {"breaks"}, EveryTracksParam: {"3"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := testInserter.NewInsertion(test.query)
+			if _, ok := err.(handler.InvalidRequestError); !ok {
+				t.Errorf("NewInsertion: got error %v, want an invalid request error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package instrumental
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+// Version descriptors are the parenthesised, bracketed or dash-separated
+// parts of a title, e.g. "Song (Instrumental)", "Song [Inst.]" or
+// "Song - Instrumental Version". A title that merely contains the word is
+// not evidence.
+var descriptorRegex = regexp.MustCompile(`[(\[]([^)\]]*)[)\]]|\s[-–—]\s(.*)$`)
+var trackDescriptorRegex = regexp.MustCompile(`(?i)\binstrumental\b|^\s*inst\.?\s*$`)
+
+// Albums are only instrumental as a whole; "Deluxe (with Instrumentals)"
+// adds instrumental versions to the originals.
+var albumDescriptorRegex = regexp.MustCompile(`(?i)^\s*(?:the\s+)?instrumentals?(?:\s+(?:version|edition|album))?\s*$`)
+
+// Override sets whether a station's tracks are instrumental where the
+// sources have it wrong or say nothing. Without a track it applies to every
+// track by the artist.
+type Override struct {
+	Artist         string `json:"artist"`
+	Track          string `json:"track"`
+	IsInstrumental bool   `json:"isInstrumental"`
+}
+
+// Detector decides whether tracks are instrumental once a handler has
+// returned them, so that every provider is treated alike. Overrides come
+// first, then any evidence from the provider itself (e.g. MusicBrainz
+// recording attributes), then version descriptors in the track and album
+// titles. Tracks without evidence are unknown.
+type Detector struct {
+	trackOverrides  map[string]bool
+	artistOverrides map[string]bool
+}
+
+func NewDetector(overrides []Override) *Detector {
+	detector := &Detector{
+		trackOverrides:  make(map[string]bool),
+		artistOverrides: make(map[string]bool),
+	}
+	for _, override := range overrides {
+		if override.Track == "" {
+			detector.artistOverrides[normalise(override.Artist)] = override.IsInstrumental
+		} else {
+			detector.trackOverrides[trackKey(override.Artist, override.Track)] = override.IsInstrumental
+		}
+	}
+	return detector
+}
+
+// Apply sets whether each track is instrumental. Non-music rows are left as
+// they are.
+func (detector *Detector) Apply(tracks []handler.TrackInfo) []handler.TrackInfo {
+	detected := make([]handler.TrackInfo, len(tracks))
+	for i, track := range tracks {
+		if track.RowType == "" {
+			track.IsInstrumental = detector.detect(track)
+		}
+		detected[i] = track
+	}
+	return detected
+}
+
+func (detector *Detector) detect(track handler.TrackInfo) string {
+	if isInstrumental, ok := detector.trackOverrides[trackKey(track.Artist, track.Track)]; ok {
+		return assessment(isInstrumental)
+	}
+	if isInstrumental, ok := detector.artistOverrides[normalise(track.Artist)]; ok {
+		return assessment(isInstrumental)
+	}
+	if track.IsInstrumental == handler.AssessmentTrue || track.IsInstrumental == handler.AssessmentFalse {
+		return track.IsInstrumental
+	}
+	if hasDescriptor(track.Track, trackDescriptorRegex) || hasDescriptor(track.Album, albumDescriptorRegex) {
+		return handler.AssessmentTrue
+	}
+	return handler.AssessmentUnknown
+}
+
+func hasDescriptor(title string, descriptorPattern *regexp.Regexp) bool {
+	for _, match := range descriptorRegex.FindAllStringSubmatch(title, -1) {
+		for _, descriptor := range match[1:] {
+			if descriptor != "" && descriptorPattern.MatchString(descriptor) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func assessment(value bool) string {
+	if value {
+		return handler.AssessmentTrue
+	}
+	return handler.AssessmentFalse
+}
+
+func trackKey(artist string, track string) string {
+	return normalise(artist) + "\x00" + normalise(track)
+}
+
+func normalise(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
@@ -0,0 +1,219 @@
+package newrelease
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+// Dates recorded to the month or year could fall anywhere within it. A
+// policy says which end of that range is compared with the window, or that
+// the date is too vague to count.
+const (
+	PrecisionPolicyEarliest = "earliest"
+	PrecisionPolicyLatest   = "latest"
+	PrecisionPolicyNever    = "never"
+)
+
+const defaultPrecisionPolicy = PrecisionPolicyLatest
+
+// LibraryEntry is a release on the station's new music list. Without a track
+// it covers every track on the album, and without an album every track by
+// the artist.
+type LibraryEntry struct {
+	Artist string `json:"artist"`
+	Album  string `json:"album"`
+	Track  string `json:"track"`
+}
+
+// ShowOverride changes the rules for one show. Anything left empty is taken
+// from the station's rules, and the show's library adds to the station's.
+type ShowOverride struct {
+	WindowDays     uint           `json:"windowDays"`
+	MonthPrecision string         `json:"monthPrecision"`
+	YearPrecision  string         `json:"yearPrecision"`
+	Library        []LibraryEntry `json:"library"`
+}
+
+// Rules decide whether tracks are new releases. Tracks on the station's new
+// music list are always new; otherwise a track is new if it was released
+// within the window, with month and year precision dates handled by their
+// policies.
+type Rules struct {
+	show              string
+	station           *Rules
+	windowDays        uint
+	precisionPolicies map[string]string
+	library           []LibraryEntry
+	shows             map[string]*Rules
+}
+
+func NewRules(
+	windowDays uint,
+	monthPrecision string,
+	yearPrecision string,
+	library []LibraryEntry,
+	shows map[string]ShowOverride,
+) *Rules {
+	rules := &Rules{
+		windowDays: windowDays,
+		precisionPolicies: map[string]string{
+			handler.ReleaseDatePrecisionMonth: orDefault(monthPrecision, defaultPrecisionPolicy),
+			handler.ReleaseDatePrecisionYear:  orDefault(yearPrecision, defaultPrecisionPolicy),
+		},
+		library: normaliseLibrary(library),
+		shows:   make(map[string]*Rules, len(shows)),
+	}
+	for name, override := range shows {
+		showRules := &Rules{
+			show:       name,
+			station:    rules,
+			windowDays: rules.windowDays,
+			precisionPolicies: map[string]string{
+				handler.ReleaseDatePrecisionMonth: orDefault(override.MonthPrecision, rules.precisionPolicies[handler.ReleaseDatePrecisionMonth]),
+				handler.ReleaseDatePrecisionYear:  orDefault(override.YearPrecision, rules.precisionPolicies[handler.ReleaseDatePrecisionYear]),
+			},
+			library: normaliseLibrary(override.Library),
+		}
+		if override.WindowDays > 0 {
+			showRules.windowDays = override.WindowDays
+		}
+		rules.shows[name] = showRules
+	}
+	return rules
+}
+
+// ValidPrecisionPolicy reports whether policy is earliest, latest or never.
+func ValidPrecisionPolicy(policy string) bool {
+	return policy == PrecisionPolicyEarliest || policy == PrecisionPolicyLatest || policy == PrecisionPolicyNever
+}
+
+// ForShow returns the rules with a show's overrides applied.
+func (rules *Rules) ForShow(show string) (*Rules, bool) {
+	showRules, ok := rules.shows[show]
+	return showRules, ok
+}
+
+// Apply records a track's release date, as the source gives it at the given
+// precision, and sets whether the track is new and why. Handlers without a
+// release date pass an empty one, so that the station's list still applies.
+func (rules *Rules) Apply(trackInfo handler.TrackInfo, releaseDate string, releaseDatePrecision string) handler.TrackInfo {
+	trackInfo.ReleaseDate = ""
+	if releaseDate != "" {
+		if _, err := parseReleaseDate(releaseDate, releaseDatePrecision); err == nil {
+			trackInfo.ReleaseDate = releaseDate
+		}
+	}
+	return rules.Reapply([]handler.TrackInfo{trackInfo})[0]
+}
+
+// Reapply decides again whether tracks are new from the release dates they
+// carry, e.g. under a show's rules. Non-music rows are left as they are.
+func (rules *Rules) Reapply(tracks []handler.TrackInfo) []handler.TrackInfo {
+	assessed := make([]handler.TrackInfo, len(tracks))
+	for i, track := range tracks {
+		if track.RowType == "" {
+			track.IsNew, track.NewReason = rules.assess(track)
+		}
+		assessed[i] = track
+	}
+	return assessed
+}
+
+func (rules *Rules) assess(track handler.TrackInfo) (bool, string) {
+	if rules.listed(track) {
+		if rules.show != "" {
+			return true, fmt.Sprintf("On the new music list for %s", rules.show)
+		}
+		return true, "On the station's new music list"
+	}
+	if rules.station != nil && rules.station.listed(track) {
+		return true, "On the station's new music list"
+	}
+	if track.ReleaseDate == "" {
+		return false, ""
+	}
+	precision := handler.ReleaseDatePrecision(track.ReleaseDate)
+	policy := rules.precisionPolicies[precision]
+	if policy == PrecisionPolicyNever {
+		return false, fmt.Sprintf("Released %s; only the %s is known, which is not counted", track.ReleaseDate, precision)
+	}
+	date, _ := parseReleaseDate(track.ReleaseDate, precision)
+	description := fmt.Sprintf("Released %s", track.ReleaseDate)
+	if precision != handler.ReleaseDatePrecisionDay {
+		if policy == PrecisionPolicyLatest {
+			date = endOfPeriod(date, precision)
+			description = fmt.Sprintf("%s, taken as the end of the %s", description, precision)
+		} else {
+			description = fmt.Sprintf("%s, taken as the start of the %s", description, precision)
+		}
+	}
+	windowStart := time.Now().UTC().AddDate(0, 0, -int(rules.windowDays))
+	if windowStart.Before(date) {
+		return true, fmt.Sprintf("%s, within the last %d days", description, rules.windowDays)
+	}
+	return false, fmt.Sprintf("%s, more than %d days ago", description, rules.windowDays)
+}
+
+func (rules *Rules) listed(track handler.TrackInfo) bool {
+	artist := normalise(track.Artist)
+	album := normalise(track.Album)
+	title := normalise(track.Track)
+	for _, entry := range rules.library {
+		if entry.Artist != artist {
+			continue
+		}
+		if entry.Album != "" && entry.Album != album {
+			continue
+		}
+		if entry.Track != "" && entry.Track != title {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func parseReleaseDate(releaseDate string, releaseDatePrecision string) (time.Time, error) {
+	switch releaseDatePrecision {
+	case handler.ReleaseDatePrecisionMonth:
+		releaseDate = fmt.Sprintf("%s-01", releaseDate)
+	case handler.ReleaseDatePrecisionYear:
+		releaseDate = fmt.Sprintf("%s-01-01", releaseDate)
+	}
+	return time.ParseInLocation(time.DateOnly, releaseDate, time.UTC)
+}
+
+// The end of a period is the last moment of it, so that a release on its
+// final day is compared as it would be with a full date.
+func endOfPeriod(start time.Time, releaseDatePrecision string) time.Time {
+	if releaseDatePrecision == handler.ReleaseDatePrecisionYear {
+		return start.AddDate(1, 0, 0).Add(-time.Nanosecond)
+	}
+	return start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+}
+
+func normaliseLibrary(library []LibraryEntry) []LibraryEntry {
+	normalised := make([]LibraryEntry, len(library))
+	for i, entry := range library {
+		normalised[i] = LibraryEntry{
+			Artist: normalise(entry.Artist),
+			Album:  normalise(entry.Album),
+			Track:  normalise(entry.Track),
+		}
+	}
+	return normalised
+}
+
+func normalise(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+func orDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,186 @@
+package newrelease
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+func TestRulesAssess(t *testing.T) {
+	stationRules := NewRules(
+		30,
+		PrecisionPolicyLatest,
+		PrecisionPolicyEarliest,
+		[]LibraryEntry{
+			{Artist: "Wheatfield", Album: "Prairie Static"},
+			{Artist: "Harbour Lights", Track: "Fog Horn"},
+		},
+		map[string]ShowOverride{
+			"Morning": {
+				WindowDays:     7,
+				MonthPrecision: PrecisionPolicyNever,
+				Library:        []LibraryEntry{{Artist: "The Vancouver Sound"}},
+			},
+		},
+	)
+	showRules, _ := stationRules.ForShow("Morning")
+	// dates are relative to the window, which ends now, and the month and
+	// year are those the window starts in, so that only their policies decide
+	now := time.Now().UTC()
+	daysAgo := func(days int) string {
+		return now.AddDate(0, 0, -days).Format(time.DateOnly)
+	}
+	month := now.AddDate(0, 0, -30).Format("2006-01")
+	year := now.AddDate(0, 0, -30).Format("2006")
+	track := func(artist string, album string, title string, releaseDate string) handler.TrackInfo {
+		trackInfo := handler.NewTrackInfo(artist, title, album, false, false)
+		trackInfo.ReleaseDate = releaseDate
+		return trackInfo
+	}
+	tests := []struct {
+		name       string
+		rules      *Rules
+		track      handler.TrackInfo
+		wantNew    bool
+		wantReason string
+	}{
+		{
+			name:       "day within the window",
+			rules:      stationRules,
+			track:      track("Artist", "Album", "Title", daysAgo(10)),
+			wantNew:    true,
+			wantReason: fmt.Sprintf("Released %s, within the last 30 days", daysAgo(10)),
+		},
+		{
+			name:       "day before the window",
+			rules:      stationRules,
+			track:      track("Artist", "Album", "Title", daysAgo(40)),
+			wantNew:    false,
+			wantReason: fmt.Sprintf("Released %s, more than 30 days ago", daysAgo(40)),
+		},
+		{
+			name:       "no release date",
+			rules:      stationRules,
+			track:      track("Artist", "Album", "Title", ""),
+			wantNew:    false,
+			wantReason: "",
+		},
+		{
+			name:       "month taken as its end",
+			rules:      stationRules,
+			track:      track("Artist", "Album", "Title", month),
+			wantNew:    true,
+			wantReason: fmt.Sprintf("Released %s, taken as the end of the month, within the last 30 days", month),
+		},
+		{
+			name:       "year taken as its start",
+			rules:      stationRules,
+			track:      track("Artist", "Album", "Title", year),
+			wantNew:    false,
+			wantReason: fmt.Sprintf("Released %s, taken as the start of the year, more than 30 days ago", year),
+		},
+		{
+			name:       "listed album",
+			rules:      stationRules,
+			track:      track(" wheatfield ", "PRAIRIE  STATIC", "Grain Elevator", daysAgo(400)),
+			wantNew:    true,
+			wantReason: "On the station's new music list",
+		},
+		{
+			name:       "other album by a listed artist",
+			rules:      stationRules,
+			track:      track("Wheatfield", "Harvest", "Grain Elevator", ""),
+			wantNew:    false,
+			wantReason: "",
+		},
+		{
+			name:       "listed track",
+			rules:      stationRules,
+			track:      track("Harbour Lights", "Coastal", "Fog Horn", ""),
+			wantNew:    true,
+			wantReason: "On the station's new music list",
+		},
+		{
+			name:       "other track by a listed artist",
+			rules:      stationRules,
+			track:      track("Harbour Lights", "Coastal", "Tide Pool", ""),
+			wantNew:    false,
+			wantReason: "",
+		},
+		{
+			name:       "show library is not the station's",
+			rules:      stationRules,
+			track:      track("The Vancouver Sound", "Northern Lights", "Aurora", daysAgo(400)),
+			wantNew:    false,
+			wantReason: fmt.Sprintf("Released %s, more than 30 days ago", daysAgo(400)),
+		},
+		{
+			name:       "show library before the release date",
+			rules:      showRules,
+			track:      track("The Vancouver Sound", "Northern Lights", "Aurora", daysAgo(400)),
+			wantNew:    true,
+			wantReason: "On the new music list for Morning",
+		},
+		{
+			name:       "station library for a show",
+			rules:      showRules,
+			track:      track("Wheatfield", "Prairie Static", "Grain Elevator", ""),
+			wantNew:    true,
+			wantReason: "On the station's new music list",
+		},
+		{
+			name:       "show window",
+			rules:      showRules,
+			track:      track("Artist", "Album", "Title", daysAgo(10)),
+			wantNew:    false,
+			wantReason: fmt.Sprintf("Released %s, more than 7 days ago", daysAgo(10)),
+		},
+		{
+			name:       "show month policy",
+			rules:      showRules,
+			track:      track("Artist", "Album", "Title", month),
+			wantNew:    false,
+			wantReason: fmt.Sprintf("Released %s; only the month is known, which is not counted", month),
+		},
+		{
+			name:       "station year policy for a show",
+			rules:      showRules,
+			track:      track("Artist", "Album", "Title", year),
+			wantNew:    false,
+			wantReason: fmt.Sprintf("Released %s, taken as the start of the year, more than 7 days ago", year),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			isNew, reason := test.rules.assess(test.track)
+			if isNew != test.wantNew || reason != test.wantReason {
+				t.Errorf("assess:\n got %t, %q\nwant %t, %q", isNew, reason, test.wantNew, test.wantReason)
+			}
+		})
+	}
+}
+
+func TestRulesApply(t *testing.T) {
+	rules := NewRules(30, "", "", nil, nil)
+	tests := []struct {
+		name        string
+		releaseDate string
+		precision   string
+		want        string
+	}{
+		{"day", "2024-03-08", handler.ReleaseDatePrecisionDay, "2024-03-08"},
+		{"month", "2024-03", handler.ReleaseDatePrecisionMonth, "2024-03"},
+		{"unreadable", "March 2024", handler.ReleaseDatePrecisionDay, ""},
+		{"none", "", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := rules.Apply(handler.NewTrackInfo("Artist", "Title", "Album", false, false), test.releaseDate, test.precision)
+			if got.ReleaseDate != test.want {
+				t.Errorf("Apply: got release date %q, want %q", got.ReleaseDate, test.want)
+			}
+		})
+	}
+}
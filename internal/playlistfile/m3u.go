@@ -0,0 +1,49 @@
+package playlistfile
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const extinfPrefix = "#EXTINF:"
+
+// An #EXTINF line describes the location line that follows it:
+//
+//	#EXTINF:215,Artist - Title
+//	Music/Artist/Title.mp3
+func parseM3u(content []byte) []Entry {
+	entries := make([]Entry, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	var pending *Entry
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, extinfPrefix):
+			info, display, _ := strings.Cut(strings.TrimPrefix(line, extinfPrefix), ",")
+			// extended players add key="value" attributes after the duration
+			durationField, _, _ := strings.Cut(info, " ")
+			duration, _ := strconv.Atoi(strings.TrimSpace(durationField))
+			artist, title, _ := SplitArtistTitle(display)
+			pending = &Entry{
+				Artist:          artist,
+				Title:           title,
+				DurationSeconds: max(duration, 0),
+			}
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			entry := Entry{}
+			if pending != nil {
+				entry = *pending
+			}
+			entry.Location = line
+			entries = append(entries, entry)
+			pending = nil
+		}
+	}
+	return entries
+}
@@ -0,0 +1,66 @@
+package playlistfile
+
+import (
+	"bytes"
+	"errors"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+type Entry struct {
+	Location        string
+	Artist          string
+	Title           string
+	Album           string
+	DurationSeconds int
+}
+
+var ErrUnsupportedFormat = errors.New("unsupported playlist format")
+
+// Parse picks a parser from the file extension, falling back to sniffing the
+// content for files that were renamed or uploaded without an extension.
+func Parse(filename string, content []byte) ([]Entry, error) {
+	content = toUtf8(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")))
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".m3u", ".m3u8":
+		return parseM3u(content), nil
+	case ".pls":
+		return parsePls(content), nil
+	case ".xspf":
+		return parseXspf(content)
+	}
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("#EXTM3U")):
+		return parseM3u(content), nil
+	case bytes.HasPrefix(bytes.ToLower(trimmed), []byte("[playlist]")):
+		return parsePls(content), nil
+	case bytes.HasPrefix(trimmed, []byte("<?xml")), bytes.HasPrefix(trimmed, []byte("<playlist")):
+		return parseXspf(content)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// SplitArtistTitle splits the "Artist - Title" display strings that M3U and
+// PLS use in place of separate fields.
+func SplitArtistTitle(display string) (string, string, bool) {
+	artist, title, found := strings.Cut(display, " - ")
+	if !found {
+		return "", strings.TrimSpace(display), false
+	}
+	return strings.TrimSpace(artist), strings.TrimSpace(title), true
+}
+
+// Plain .m3u and .pls files written on Windows are usually Latin-1 rather
+// than UTF-8.
+func toUtf8(content []byte) []byte {
+	if utf8.Valid(content) {
+		return content
+	}
+	runes := make([]rune, len(content))
+	for i, b := range content {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
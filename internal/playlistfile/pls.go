@@ -0,0 +1,59 @@
+package playlistfile
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PLS files are INI-like, with numbered FileN, TitleN and LengthN keys that
+// may appear in any order.
+func parsePls(content []byte) []Entry {
+	entriesByNumber := make(map[int]*Entry)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !found {
+			continue
+		}
+		lowerKey := strings.ToLower(strings.TrimSpace(key))
+		var field string
+		for _, prefix := range []string{"file", "title", "length"} {
+			if strings.HasPrefix(lowerKey, prefix) {
+				field = prefix
+				break
+			}
+		}
+		number, err := strconv.Atoi(strings.TrimPrefix(lowerKey, field))
+		if field == "" || err != nil {
+			continue
+		}
+		entry, ok := entriesByNumber[number]
+		if !ok {
+			entry = &Entry{}
+			entriesByNumber[number] = entry
+		}
+		value = strings.TrimSpace(value)
+		switch field {
+		case "file":
+			entry.Location = value
+		case "title":
+			entry.Artist, entry.Title, _ = SplitArtistTitle(value)
+		case "length":
+			duration, _ := strconv.Atoi(value)
+			entry.DurationSeconds = max(duration, 0)
+		}
+	}
+	numbers := make([]int, 0, len(entriesByNumber))
+	for number := range entriesByNumber {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	entries := make([]Entry, 0, len(numbers))
+	for _, number := range numbers {
+		entries = append(entries, *entriesByNumber[number])
+	}
+	return entries
+}
@@ -0,0 +1,38 @@
+package playlistfile
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+type xspfPlaylist struct {
+	Tracks []struct {
+		Locations []string `xml:"location"`
+		Creator   string   `xml:"creator"`
+		Title     string   `xml:"title"`
+		Album     string   `xml:"album"`
+		Duration  int      `xml:"duration"`
+	} `xml:"trackList>track"`
+}
+
+func parseXspf(content []byte) ([]Entry, error) {
+	var playlist xspfPlaylist
+	if err := xml.Unmarshal(content, &playlist); err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		entry := Entry{
+			Artist: strings.TrimSpace(track.Creator),
+			Title:  strings.TrimSpace(track.Title),
+			Album:  strings.TrimSpace(track.Album),
+			// XSPF durations are in milliseconds
+			DurationSeconds: track.Duration / 1000,
+		}
+		if len(track.Locations) > 0 {
+			entry.Location = strings.TrimSpace(track.Locations[0])
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
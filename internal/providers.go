@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+)
+
+const (
+	// checking credentials costs a request to the provider, and they rarely
+	// change while the server runs
+	credentialsCacheDuration = 5 * time.Minute
+	credentialsCheckTimeout  = 10 * time.Second
+)
+
+type providerRegistration struct {
+	info               handler.ProviderInfo
+	credentialsHandler handler.TrackInfoCredentialsHandler
+	credentialsCheck   *credentialsCheck
+}
+
+type credentialsCheck struct {
+	mutex     sync.Mutex
+	status    string
+	checkedAt time.Time
+	running   chan struct{}
+}
+
+// Icons are optional and found by convention as "{identifier}.png" in the
+// client assets.
+func newProviderRegistration(trackInfoHandler handler.TrackInfoHandler, capabilities []string) providerRegistration {
+	registration := providerRegistration{
+		info: handler.ProviderInfo{
+			Identifier:   trackInfoHandler.Identifier(),
+			Capabilities: capabilities,
+			UrlPatterns:  make([]handler.UrlPatternInfo, 0),
+			Credentials:  handler.CredentialsNotRequired,
+		},
+	}
+	icon := fmt.Sprintf("%s.png", trackInfoHandler.Identifier())
+	if _, err := fs.Stat(assetsDirectory, fmt.Sprintf("client/assets/%s", icon)); err == nil {
+		registration.info.Icon = icon
+	}
+	if urlHandler, ok := trackInfoHandler.(handler.TrackInfoUrlHandler); ok {
+		for _, urlPattern := range urlHandler.UrlPatterns() {
+			registration.info.UrlPatterns = append(registration.info.UrlPatterns, handler.UrlPatternInfo{
+				Type:    constants.RequestTypeNames[urlPattern.RequestType],
+				Pattern: urlPattern.Pattern.String(),
+			})
+		}
+	}
+	if credentialsHandler, ok := trackInfoHandler.(handler.TrackInfoCredentialsHandler); ok {
+		registration.credentialsHandler = credentialsHandler
+		registration.credentialsCheck = &credentialsCheck{}
+	}
+	return registration
+}
+
+// createProvidersFunction returns a handler function that lists every
+// registered provider. Credentials are checked concurrently and cached for a
+// few minutes, as they may expire or be revoked while the server runs.
+func createProvidersFunction(registrations []providerRegistration) func(*http.Request) ([]handler.ProviderInfo, error) {
+	return func(request *http.Request) ([]handler.ProviderInfo, error) {
+		providerInfos := make([]handler.ProviderInfo, len(registrations))
+		var waitGroup sync.WaitGroup
+		for i, registration := range registrations {
+			providerInfos[i] = registration.info
+			if registration.credentialsHandler == nil {
+				continue
+			}
+			waitGroup.Add(1)
+			go func(i int, registration providerRegistration) {
+				defer waitGroup.Done()
+				providerInfos[i].Credentials = registration.credentialsCheck.result(registration.credentialsHandler)
+			}(i, registration)
+		}
+		waitGroup.Wait()
+		return providerInfos, nil
+	}
+}
+
+// result returns the cached status while it is fresh, otherwise checks the
+// credentials again. Concurrent requests share one check, and a check still
+// running at the timeout is reported as unknown and left to finish and cache
+// its result for later requests. Unknown results are not cached so that
+// they are retried.
+func (check *credentialsCheck) result(credentialsHandler handler.TrackInfoCredentialsHandler) string {
+	check.mutex.Lock()
+	if check.status != "" && check.status != handler.CredentialsUnknown && time.Since(check.checkedAt) < credentialsCacheDuration {
+		defer check.mutex.Unlock()
+		return check.status
+	}
+	if check.running == nil {
+		running := make(chan struct{})
+		check.running = running
+		go func() {
+			status := credentialsStatus(credentialsHandler.ValidateCredentials())
+			check.mutex.Lock()
+			check.status, check.checkedAt, check.running = status, time.Now(), nil
+			check.mutex.Unlock()
+			close(running)
+		}()
+	}
+	running := check.running
+	check.mutex.Unlock()
+	timer := time.NewTimer(credentialsCheckTimeout)
+	defer timer.Stop()
+	select {
+	case <-running:
+		check.mutex.Lock()
+		defer check.mutex.Unlock()
+		return check.status
+	case <-timer.C:
+		return handler.CredentialsUnknown
+	}
+}
+
+// Only a provider's rejection says the credentials are invalid. A provider
+// that cannot be reached says nothing about them.
+func credentialsStatus(err error) string {
+	if err == nil {
+		return handler.CredentialsValid
+	}
+	if _, ok := err.(handler.HandlerAuthenticationError); ok {
+		return handler.CredentialsInvalid
+	}
+	return handler.CredentialsUnknown
+}
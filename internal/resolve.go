@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/captaincoordinates/cick-playlister/internal/constants"
+	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/insert"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	resolveUrlParam           = "url"
+	maximumShortLinkBodySize  = 1024 * 1024
+	maximumShortLinkRedirects = 5
+	shortLinkTimeout          = 10 * time.Second
+)
+
+var embeddedUrlRegex = regexp.MustCompile(`https?://[^\s"'<>\\]+`)
+
+// Only these hosts are fetched when a link is not recognised directly, so
+// the resolver cannot be pointed at arbitrary (e.g. internal) addresses.
+var shortLinkHosts = map[string]bool{
+	"spotify.link":      true,
+	"spotify.app.link":  true,
+	"on.soundcloud.com": true,
+	"deezer.page.link":  true,
+	"dzr.page.link":     true,
+	"link.deezer.com":   true,
+	"youtu.be":          true,
+}
+
+var errShortLinkRedirect = errors.New("short link redirected to an unsupported location")
+
+type resolvedLink struct {
+	trackInfoHandler handler.TrackInfoHandler
+	requestType      constants.RequestType
+	identifier       string
+}
+
+// createResolveFunction returns a handler function that accepts any link
+// recognised by one of the trackInfoHandlers and fetches it with the matching
+// capability. Short links (spotify.link, on.soundcloud.com, etc.) are followed
+// to the link they stand for.
+func createResolveFunction(trackInfoHandlers []handler.TrackInfoHandler, enrichment trackEnrichment, rowInserter *insert.Inserter) func(*http.Request) (handler.ResolvedInfo, error) {
+	return func(request *http.Request) (handler.ResolvedInfo, error) {
+		link := strings.TrimSpace(request.URL.Query().Get(resolveUrlParam))
+		if link == "" {
+			return handler.ResolvedInfo{}, handler.NewInvalidRequestError(fmt.Sprintf("'%s' is required", resolveUrlParam))
+		}
+		resolved, err := resolveLink(trackInfoHandlers, link)
+		if err != nil {
+			return handler.ResolvedInfo{}, err
+		}
+		collectionInfo, err := fetchResolvedLink(resolved, request, enrichment, rowInserter)
+		if err != nil {
+			return handler.ResolvedInfo{}, err
+		}
+		return handler.ResolvedInfo{
+			Provider:            resolved.trackInfoHandler.Identifier(),
+			Type:                constants.RequestTypeNames[resolved.requestType],
+			TrackCollectionInfo: collectionInfo,
+		}, nil
+	}
+}
+
+func resolveLink(trackInfoHandlers []handler.TrackInfoHandler, link string) (resolvedLink, error) {
+	if resolved, ok := matchLink(trackInfoHandlers, link); ok {
+		return resolved, nil
+	}
+	return followShortLink(trackInfoHandlers, link)
+}
+
+func matchLink(trackInfoHandlers []handler.TrackInfoHandler, link string) (resolvedLink, bool) {
+	for _, trackInfoHandler := range trackInfoHandlers {
+		urlHandler, ok := trackInfoHandler.(handler.TrackInfoUrlHandler)
+		if !ok {
+			continue
+		}
+		for _, urlPattern := range urlHandler.UrlPatterns() {
+			if identifier, ok := urlPattern.Match(link); ok {
+				return resolvedLink{
+					trackInfoHandler: trackInfoHandler,
+					requestType:      urlPattern.RequestType,
+					identifier:       identifier,
+				}, true
+			}
+		}
+	}
+	return resolvedLink{}, false
+}
+
+// Most short links redirect, but some (spotify.link) answer with a page that
+// redirects in the browser, so the page is searched for a recognised link.
+// Redirects are followed only between short-link hosts and stop as soon as
+// they reach a recognised link, which is matched without being fetched.
+func followShortLink(trackInfoHandlers []handler.TrackInfoHandler, link string) (resolvedLink, error) {
+	parsed, err := url.Parse(link)
+	if err != nil || !isShortLink(parsed) {
+		return resolvedLink{}, unsupportedLinkError(link)
+	}
+	var resolved resolvedLink
+	client := &http.Client{
+		Timeout: shortLinkTimeout,
+		CheckRedirect: func(redirect *http.Request, via []*http.Request) error {
+			if match, ok := matchLink(trackInfoHandlers, redirect.URL.String()); ok {
+				resolved = match
+				return http.ErrUseLastResponse
+			}
+			if len(via) >= maximumShortLinkRedirects || !isShortLink(redirect.URL) {
+				return errShortLinkRedirect
+			}
+			return nil
+		},
+	}
+	resp, err := client.Get(parsed.String())
+	if err != nil {
+		return resolvedLink{}, unsupportedLinkError(link)
+	}
+	defer resp.Body.Close()
+	if resolved.trackInfoHandler != nil {
+		return resolved, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maximumShortLinkBodySize))
+	if err != nil {
+		return resolvedLink{}, unsupportedLinkError(link)
+	}
+	for _, embeddedUrl := range embeddedUrlRegex.FindAllString(string(body), -1) {
+		if resolved, ok := matchLink(trackInfoHandlers, strings.ReplaceAll(embeddedUrl, "&amp;", "&")); ok {
+			return resolved, nil
+		}
+	}
+	return resolvedLink{}, unsupportedLinkError(link)
+}
+
+func isShortLink(link *url.URL) bool {
+	return (link.Scheme == "http" || link.Scheme == "https") && shortLinkHosts[strings.ToLower(link.Hostname())]
+}
+
+func unsupportedLinkError(link string) error {
+	return handler.NewInvalidRequestError(fmt.Sprintf("'%s' is not a supported link", link))
+}
+
+// The identifier is passed to the handler as the path parameter it would
+// have received from its own route. Other query parameters pass through.
+func fetchResolvedLink(resolved resolvedLink, request *http.Request, enrichment trackEnrichment, rowInserter *insert.Inserter) (handler.TrackCollectionInfo, error) {
+	request = mux.SetURLVars(request, map[string]string{
+		constants.RequestTypeIdentifierParams[resolved.requestType]: resolved.identifier,
+	})
+	switch resolved.requestType {
+	case constants.PlaylistRequestType:
+		if playlistHandler, ok := resolved.trackInfoHandler.(handler.TrackInfoPlaylistHandler); ok {
+			return enrichment.collection(withCollectionOptions(rowInserter, playlistHandler.Playlist))(request)
+		}
+	case constants.AlbumRequestType:
+		if albumHandler, ok := resolved.trackInfoHandler.(handler.TrackInfoAlbumHandler); ok {
+			return enrichment.collection(withCollectionOptions(rowInserter, albumHandler.Album))(request)
+		}
+	case constants.ShowRequestType:
+		if showHandler, ok := resolved.trackInfoHandler.(handler.TrackInfoShowHandler); ok {
+			return enrichment.collection(withCollectionOptions(rowInserter, showHandler.Show))(request)
+		}
+	case constants.TrackRequestType:
+		if trackHandler, ok := resolved.trackInfoHandler.(handler.TrackInfoTrackHandler); ok {
+			trackInfo, err := enrichment.track(trackHandler.Track)(request)
+			if err != nil {
+				return handler.EmptyTrackCollectionInfo, err
+			}
+			return handler.NewTrackCollectionInfo([]handler.TrackInfo{trackInfo}, resolved.identifier), nil
+		}
+	case constants.EpisodeRequestType:
+		if episodeHandler, ok := resolved.trackInfoHandler.(handler.TrackInfoEpisodeHandler); ok {
+			trackInfo, err := enrichment.track(episodeHandler.Episode)(request)
+			if err != nil {
+				return handler.EmptyTrackCollectionInfo, err
+			}
+			return handler.NewTrackCollectionInfo([]handler.TrackInfo{trackInfo}, resolved.identifier), nil
+		}
+	}
+	return handler.EmptyTrackCollectionInfo, handler.NewInvalidRequestError(
+		fmt.Sprintf(
+			"%s does not support %s requests",
+			resolved.trackInfoHandler.Identifier(),
+			constants.RequestTypeNames[resolved.requestType],
+		),
+	)
+}
@@ -7,10 +7,28 @@ import (
 	"io/fs"
 	"net/http"
 
+	"github.com/captaincoordinates/cick-playlister/internal/cancon"
 	"github.com/captaincoordinates/cick-playlister/internal/config"
 	"github.com/captaincoordinates/cick-playlister/internal/constants"
 	"github.com/captaincoordinates/cick-playlister/internal/handler"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/applemusic"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/automationlogimport"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/bandcamp"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/deezer"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/discogs"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/djhistoryimport"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/localfiles"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/mixcloud"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/musicbrainz"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/playlistimport"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/soundcloud"
 	"github.com/captaincoordinates/cick-playlister/internal/handler/spotify"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/textimport"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/tidal"
+	"github.com/captaincoordinates/cick-playlister/internal/handler/youtube"
+	"github.com/captaincoordinates/cick-playlister/internal/insert"
+	"github.com/captaincoordinates/cick-playlister/internal/instrumental"
+	"github.com/captaincoordinates/cick-playlister/internal/newrelease"
 
 	"github.com/gorilla/mux"
 )
@@ -30,13 +48,101 @@ func ConfigureRouter(
 	router := mux.NewRouter()
 	router.Use(corsMiddleware)
 	credentialsConfig := config.NewCredentialsConfig()
-	for _, trackInfoHandler := range []handler.TrackInfoHandler{
-		spotify.NewSpotifyHandler(
-			credentialsConfig.Spotify.ClientID,
-			credentialsConfig.Spotify.ClientSecret,
-			newReleaseDays,
+	newReleaseConfig := config.NewNewReleaseConfig()
+	newReleaseRules := newrelease.NewRules(
+		newReleaseDays,
+		newReleaseConfig.MonthPrecision,
+		newReleaseConfig.YearPrecision,
+		newReleaseConfig.Library,
+		newReleaseConfig.Shows,
+	)
+	spotifyHandler := spotify.NewSpotifyHandler(
+		credentialsConfig.Spotify.ClientID,
+		credentialsConfig.Spotify.ClientSecret,
+		newReleaseRules,
+	)
+	localFilesHandler := localfiles.NewLocalFilesHandler(
+		config.NewLocalFilesConfig().Roots,
+		newReleaseRules,
+	)
+	musicBrainzHandler := musicbrainz.NewMusicBrainzHandler(
+		newReleaseRules,
+	)
+	enrichment := trackEnrichment{
+		instrumentalDetector: instrumental.NewDetector(
+			config.NewInstrumentalConfig().Overrides,
 		),
-	} {
+		canConAssessor: cancon.NewAssessor(
+			config.NewCanConConfig().Artists,
+			musicBrainzHandler.ArtistOrigin,
+		),
+		newReleaseRules: newReleaseRules,
+	}
+	insertConfig := config.NewInsertConfig()
+	rowInserter := insert.NewInserter(
+		insertConfig.Entries,
+		insertConfig.Templates,
+	)
+	trackInfoHandlers := []handler.TrackInfoHandler{
+		spotifyHandler,
+		applemusic.NewAppleMusicHandler(
+			credentialsConfig.AppleMusic.TeamID,
+			credentialsConfig.AppleMusic.KeyID,
+			credentialsConfig.AppleMusic.PrivateKey,
+			credentialsConfig.AppleMusic.Storefront,
+			newReleaseRules,
+		),
+		deezer.NewDeezerHandler(
+			newReleaseRules,
+		),
+		bandcamp.NewBandcampHandler(
+			config.NewBandcampConfig().CustomDomains,
+			newReleaseRules,
+		),
+		youtube.NewYouTubeHandler(
+			credentialsConfig.YouTube.APIKey,
+			newReleaseRules,
+		),
+		soundcloud.NewSoundCloudHandler(
+			credentialsConfig.SoundCloud.ClientID,
+			credentialsConfig.SoundCloud.ClientSecret,
+			newReleaseRules,
+		),
+		tidal.NewTidalHandler(
+			credentialsConfig.Tidal.ClientID,
+			credentialsConfig.Tidal.ClientSecret,
+			credentialsConfig.Tidal.CountryCode,
+			newReleaseRules,
+		),
+		mixcloud.NewMixcloudHandler(
+			newReleaseRules,
+		),
+		discogs.NewDiscogsHandler(
+			credentialsConfig.Discogs.Token,
+			newReleaseRules,
+		),
+		musicBrainzHandler,
+		localFilesHandler,
+		playlistimport.NewPlaylistImportHandler(
+			localFilesHandler.TrackInfoFromPath,
+			newReleaseRules,
+		),
+		djhistoryimport.NewDjHistoryImportHandler(
+			constants.DefaultMinimumPlayedSeconds,
+			newReleaseRules,
+		),
+		automationlogimport.NewAutomationLogImportHandler(
+			config.NewAutomationLogConfig().Mappings,
+			newReleaseRules,
+		),
+		textimport.NewTextImportHandler(
+			spotifyHandler.Search,
+			spotifyHandler.ValidateCredentials,
+			newReleaseRules,
+		),
+	}
+	providerRegistrations := make([]providerRegistration, 0, len(trackInfoHandlers))
+	for _, trackInfoHandler := range trackInfoHandlers {
 		handlerCapabilities := make([]string, 0)
 		if playlistHandler, ok := trackInfoHandler.(handler.TrackInfoPlaylistHandler); ok {
 			router.HandleFunc(
@@ -46,7 +152,7 @@ func ConfigureRouter(
 					constants.RequestTypeNames[constants.PlaylistRequestType],
 					constants.PlaylistIdentifierParam,
 				),
-				createHandlerFunctionClosure(playlistHandler.Playlist),
+				createHandlerFunctionClosure(enrichment.collection(withCollectionOptions(rowInserter, playlistHandler.Playlist))),
 			)
 			handlerCapabilities = append(handlerCapabilities, constants.RequestTypeNames[constants.PlaylistRequestType])
 		}
@@ -58,9 +164,9 @@ func ConfigureRouter(
 					constants.RequestTypeNames[constants.AlbumRequestType],
 					constants.AlbumIdentifierParam,
 				),
-				createHandlerFunctionClosure(albumHandler.Album),
+				createHandlerFunctionClosure(enrichment.collection(withCollectionOptions(rowInserter, albumHandler.Album))),
 			)
-			handlerCapabilities = append(handlerCapabilities, constants.RequestTypeNames[constants.TrackRequestType])
+			handlerCapabilities = append(handlerCapabilities, constants.RequestTypeNames[constants.AlbumRequestType])
 		}
 		if trackHandler, ok := trackInfoHandler.(handler.TrackInfoTrackHandler); ok {
 			router.HandleFunc(
@@ -70,11 +176,50 @@ func ConfigureRouter(
 					constants.RequestTypeNames[constants.TrackRequestType],
 					constants.TrackIdentifierParam,
 				),
-				createHandlerFunctionClosure(trackHandler.Track),
+				createHandlerFunctionClosure(enrichment.track(trackHandler.Track)),
 			)
 			handlerCapabilities = append(handlerCapabilities, constants.RequestTypeNames[constants.TrackRequestType])
 		}
+		if episodeHandler, ok := trackInfoHandler.(handler.TrackInfoEpisodeHandler); ok {
+			router.HandleFunc(
+				fmt.Sprintf(
+					"/%s/%s/{%s:.+}",
+					trackInfoHandler.Identifier(),
+					constants.RequestTypeNames[constants.EpisodeRequestType],
+					constants.EpisodeIdentifierParam,
+				),
+				createHandlerFunctionClosure(enrichment.track(episodeHandler.Episode)),
+			)
+			handlerCapabilities = append(handlerCapabilities, constants.RequestTypeNames[constants.EpisodeRequestType])
+		}
+		if showHandler, ok := trackInfoHandler.(handler.TrackInfoShowHandler); ok {
+			router.HandleFunc(
+				fmt.Sprintf(
+					"/%s/%s/{%s:.+}",
+					trackInfoHandler.Identifier(),
+					constants.RequestTypeNames[constants.ShowRequestType],
+					constants.ShowIdentifierParam,
+				),
+				createHandlerFunctionClosure(enrichment.collection(withCollectionOptions(rowInserter, showHandler.Show))),
+			)
+			handlerCapabilities = append(handlerCapabilities, constants.RequestTypeNames[constants.ShowRequestType])
+		}
+		if uploadHandler, ok := trackInfoHandler.(handler.TrackInfoUploadHandler); ok {
+			router.HandleFunc(
+				fmt.Sprintf(
+					"/%s/%s",
+					trackInfoHandler.Identifier(),
+					constants.RequestTypeNames[constants.UploadRequestType],
+				),
+				createHandlerFunctionClosure(enrichment.collection(withCollectionOptions(rowInserter, uploadHandler.Upload))),
+			).Methods(http.MethodPost, http.MethodOptions)
+			handlerCapabilities = append(handlerCapabilities, constants.RequestTypeNames[constants.UploadRequestType])
+		}
+		providerRegistrations = append(providerRegistrations, newProviderRegistration(trackInfoHandler, handlerCapabilities))
 	}
+	router.HandleFunc("/providers", createHandlerFunctionClosure(createProvidersFunction(providerRegistrations)))
+	router.HandleFunc("/resolve", createHandlerFunctionClosure(createResolveFunction(trackInfoHandlers, enrichment, rowInserter)))
+	router.HandleFunc("/batch", createHandlerFunctionClosure(createBatchFunction(trackInfoHandlers, enrichment, rowInserter))).Methods(http.MethodPost, http.MethodOptions)
 	router.PathPrefix("/docs/").Handler(http.FileServer(http.FS(fs.FS(docsDirectory))))
 	router.PathPrefix("/client/dist/").Handler(http.FileServer(http.FS(fs.FS(clientDirectory))))
 	router.PathPrefix("/client/assets/").Handler(http.FileServer(http.FS(fs.FS(assetsDirectory))))
@@ -112,10 +257,43 @@ func createHandlerFunctionClosure[T any](handlerFunction func(*http.Request) (T,
 	}
 }
 
+// withCollectionOptions narrows a track collection to the tracks chosen by
+// the request's selection parameters, inserts any station IDs and PSAs asked
+// for between them and estimates air times if a show start is given.
+// Parameters are validated before the provider is called.
+func withCollectionOptions(rowInserter *insert.Inserter, handlerFunction func(*http.Request) (handler.TrackCollectionInfo, error)) func(*http.Request) (handler.TrackCollectionInfo, error) {
+	return func(request *http.Request) (handler.TrackCollectionInfo, error) {
+		trackSelection, err := handler.NewTrackSelection(request.URL.Query())
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		insertion, err := rowInserter.NewInsertion(request.URL.Query())
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		trackSchedule, err := handler.NewTrackSchedule(request.URL.Query())
+		if err != nil {
+			return handler.EmptyTrackCollectionInfo, err
+		}
+		collectionInfo, err := handlerFunction(request)
+		if err != nil {
+			return collectionInfo, err
+		}
+		collectionInfo.Tracks = insertion.Apply(trackSelection.Apply(collectionInfo.Tracks))
+		return trackSchedule.Apply(collectionInfo), nil
+	}
+}
+
 func statusCodeFromError(err error) (int, string) {
 	if _, ok := err.(handler.InvalidTrackCollectionIdError); ok {
 		return http.StatusBadRequest, err.Error()
 	}
+	if _, ok := err.(handler.InvalidTrackIdError); ok {
+		return http.StatusBadRequest, err.Error()
+	}
+	if _, ok := err.(handler.InvalidRequestError); ok {
+		return http.StatusBadRequest, err.Error()
+	}
 	if _, ok := err.(handler.HandlerAuthenticationError); ok {
 		return http.StatusUnauthorized, err.Error()
 	}
@@ -125,6 +303,9 @@ func statusCodeFromError(err error) (int, string) {
 	if _, ok := err.(handler.TrackNotFoundError); ok {
 		return http.StatusNotFound, err.Error()
 	}
+	if _, ok := err.(handler.UpstreamError); ok {
+		return http.StatusBadGateway, err.Error()
+	}
 	if _, ok := err.(handler.InternalError); ok {
 		return http.StatusInternalServerError, err.Error()
 	}
@@ -138,7 +319,7 @@ func jsonResponseType(writer *http.ResponseWriter) {
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		writer.Header().Set("Access-Control-Allow-Origin", "*")
-		writer.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
 		writer.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length")
 		if request.Method == "OPTIONS" {
 			writer.WriteHeader(http.StatusOK)
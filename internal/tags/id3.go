@@ -0,0 +1,191 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const id3v1Size = 128
+
+var errInvalidId3 = errors.New("invalid ID3v2 tag")
+
+func readId3v2(reader io.ReadSeeker) (Tags, error) {
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return Tags{}, err
+	}
+	majorVersion := header[3]
+	flags := header[5]
+	size := syncSafeInt(header[6:10])
+	if majorVersion < 2 || majorVersion > 4 || size > maximumMetadataBytes {
+		return Tags{}, errInvalidId3
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return Tags{}, err
+	}
+	// v2.4 unsynchronises per frame; earlier versions unsynchronise the whole tag
+	if flags&0x80 != 0 && majorVersion < 4 {
+		body = bytes.ReplaceAll(body, []byte{0xFF, 0x00}, []byte{0xFF})
+	}
+	if flags&0x40 != 0 && majorVersion > 2 && len(body) >= 4 {
+		extendedHeaderSize := int(binary.BigEndian.Uint32(body[:4]))
+		if majorVersion == 4 {
+			extendedHeaderSize = syncSafeInt(body[:4])
+		} else {
+			extendedHeaderSize += 4
+		}
+		if extendedHeaderSize > len(body) {
+			return Tags{}, errInvalidId3
+		}
+		body = body[extendedHeaderSize:]
+	}
+	idLength, headerLength := 4, 10
+	if majorVersion == 2 {
+		idLength, headerLength = 3, 6
+	}
+	frames := make(map[string]string)
+	for len(body) >= headerLength && body[0] != 0 {
+		id := string(body[:idLength])
+		var frameSize int
+		switch majorVersion {
+		case 2:
+			frameSize = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+		case 3:
+			frameSize = int(binary.BigEndian.Uint32(body[4:8]))
+		case 4:
+			frameSize = syncSafeInt(body[4:8])
+		}
+		if frameSize <= 0 || headerLength+frameSize > len(body) {
+			break
+		}
+		frame := body[headerLength : headerLength+frameSize]
+		if majorVersion == 4 && body[9]&0x02 != 0 {
+			frame = bytes.ReplaceAll(frame, []byte{0xFF, 0x00}, []byte{0xFF})
+		}
+		if strings.HasPrefix(id, "T") {
+			if _, ok := frames[id]; !ok {
+				frames[id] = decodeId3Text(frame)
+			}
+		}
+		body = body[headerLength+frameSize:]
+	}
+	tags := Tags{
+		Artist:      firstFrame(frames, "TPE1", "TP1"),
+		AlbumArtist: firstFrame(frames, "TPE2", "TP2"),
+		Title:       firstFrame(frames, "TIT2", "TT2"),
+		Album:       firstFrame(frames, "TALB", "TAL"),
+		Date:        firstFrame(frames, "TDRC", "TDRL", "TYER", "TYE", "TDOR", "TOR"),
+	}
+	tags.TrackNumber, tags.TrackTotal = parseTrackNumber(firstFrame(frames, "TRCK", "TRK"))
+	tags.DiscNumber, _ = parseTrackNumber(firstFrame(frames, "TPOS", "TPA"))
+	// TLEN is in milliseconds; most taggers leave it out, so the audio is measured instead
+	if milliseconds, err := strconv.Atoi(firstFrame(frames, "TLEN", "TLE")); err == nil && milliseconds > 0 {
+		tags.Duration = (milliseconds + 500) / 1000
+	} else {
+		audioStart := int64(10 + size)
+		if flags&0x10 != 0 {
+			audioStart += 10
+		}
+		tags.Duration = mpegDuration(reader, audioStart)
+	}
+	return tags, nil
+}
+
+func readId3v1(reader io.ReadSeeker) (Tags, error) {
+	if _, err := reader.Seek(-id3v1Size, io.SeekEnd); err != nil {
+		return Tags{}, ErrUnsupportedFormat
+	}
+	tag := make([]byte, id3v1Size)
+	if _, err := io.ReadFull(reader, tag); err != nil || !bytes.HasPrefix(tag, []byte("TAG")) {
+		// an untagged MP3 still has a duration
+		if duration := mpegDuration(reader, 0); duration > 0 {
+			return Tags{Duration: duration}, nil
+		}
+		return Tags{}, ErrUnsupportedFormat
+	}
+	tags := Tags{
+		Title:  latin1String(tag[3:33]),
+		Artist: latin1String(tag[33:63]),
+		Album:  latin1String(tag[63:93]),
+		Date:   latin1String(tag[93:97]),
+	}
+	// ID3v1.1 stores the track number in the last byte of the comment field
+	if tag[125] == 0 && tag[126] != 0 {
+		tags.TrackNumber = int(tag[126])
+	}
+	tags.Duration = mpegDuration(reader, 0)
+	return tags, nil
+}
+
+func decodeId3Text(frame []byte) string {
+	if len(frame) < 2 {
+		return ""
+	}
+	var values []string
+	switch frame[0] {
+	case 1, 2:
+		values = strings.Split(decodeUtf16(frame[1:], frame[0] == 2), "\x00")
+	case 3:
+		values = strings.Split(string(frame[1:]), "\x00")
+	default:
+		values = strings.Split(latin1String(frame[1:]), "\x00")
+	}
+	nonEmpty := make([]string, 0, len(values))
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			nonEmpty = append(nonEmpty, trimmed)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
+
+// Each value in a UTF-16 frame may carry its own byte order mark.
+func decodeUtf16(data []byte, bigEndian bool) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		switch {
+		case data[i] == 0xFF && data[i+1] == 0xFE:
+			bigEndian = false
+			continue
+		case data[i] == 0xFE && data[i+1] == 0xFF:
+			bigEndian = true
+			continue
+		}
+		if bigEndian {
+			units = append(units, binary.BigEndian.Uint16(data[i:i+2]))
+		} else {
+			units = append(units, binary.LittleEndian.Uint16(data[i:i+2]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+func latin1String(data []byte) string {
+	if index := bytes.IndexByte(data, 0); index >= 0 {
+		data = data[:index]
+	}
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return strings.TrimSpace(string(runes))
+}
+
+func syncSafeInt(data []byte) int {
+	return int(data[0]&0x7F)<<21 | int(data[1]&0x7F)<<14 | int(data[2]&0x7F)<<7 | int(data[3]&0x7F)
+}
+
+func firstFrame(frames map[string]string, ids ...string) string {
+	for _, id := range ids {
+		if value := frames[id]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
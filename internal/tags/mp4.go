@@ -0,0 +1,179 @@
+package tags
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var errInvalidMp4 = errors.New("invalid MP4 atom")
+
+type mp4Atom struct {
+	atomType    string
+	payloadSize int64
+}
+
+// Metadata lives at moov/udta/meta/ilst, where each child atom is named
+// after the field it holds and wraps its value in a "data" atom.
+func readMp4(reader io.ReadSeeker) (Tags, error) {
+	fileSize, err := reader.Seek(0, io.SeekEnd)
+	if err != nil {
+		return Tags{}, err
+	}
+	duration := mp4Duration(reader, fileSize)
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return Tags{}, err
+	}
+	ilstSize, err := findAtomPath(reader, fileSize, []string{"moov", "udta", "meta", "ilst"})
+	if err != nil {
+		return Tags{}, err
+	}
+	if ilstSize > maximumMetadataBytes {
+		return Tags{}, errInvalidMp4
+	}
+	ilst := make([]byte, ilstSize)
+	if _, err := io.ReadFull(reader, ilst); err != nil {
+		return Tags{}, err
+	}
+	tags := Tags{Duration: duration}
+	for len(ilst) >= 8 {
+		itemSize := int(binary.BigEndian.Uint32(ilst[:4]))
+		if itemSize < 8 || itemSize > len(ilst) {
+			return tags, errInvalidMp4
+		}
+		itemType := string(ilst[4:8])
+		value := mp4DataValue(ilst[8:itemSize])
+		switch itemType {
+		case "\xa9ART":
+			tags.Artist = string(value)
+		case "aART":
+			tags.AlbumArtist = string(value)
+		case "\xa9nam":
+			tags.Title = string(value)
+		case "\xa9alb":
+			tags.Album = string(value)
+		case "\xa9day":
+			tags.Date = string(value)
+		case "trkn":
+			if len(value) >= 6 {
+				tags.TrackNumber = int(binary.BigEndian.Uint16(value[2:4]))
+				tags.TrackTotal = int(binary.BigEndian.Uint16(value[4:6]))
+			}
+		case "disk":
+			if len(value) >= 4 {
+				tags.DiscNumber = int(binary.BigEndian.Uint16(value[2:4]))
+			}
+		}
+		ilst = ilst[itemSize:]
+	}
+	return tags, nil
+}
+
+// The movie header at moov/mvhd holds the duration in units of its own
+// timescale. Version 1 headers use 64 bit times.
+func mp4Duration(reader io.ReadSeeker, fileSize int64) int {
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return 0
+	}
+	mvhdSize, err := findAtomPath(reader, fileSize, []string{"moov", "mvhd"})
+	if err != nil || mvhdSize < 32 {
+		return 0
+	}
+	mvhd := make([]byte, 32)
+	if _, err := io.ReadFull(reader, mvhd); err != nil {
+		return 0
+	}
+	var timescale, duration int64
+	if mvhd[0] == 1 {
+		timescale = int64(binary.BigEndian.Uint32(mvhd[20:24]))
+		duration = int64(binary.BigEndian.Uint64(mvhd[24:32]))
+	} else {
+		timescale = int64(binary.BigEndian.Uint32(mvhd[12:16]))
+		duration = int64(binary.BigEndian.Uint32(mvhd[16:20]))
+	}
+	if timescale == 0 || duration <= 0 {
+		return 0
+	}
+	return int((duration + timescale/2) / timescale)
+}
+
+// findAtomPath leaves the reader positioned at the payload of the last atom
+// in path and returns that payload's size.
+func findAtomPath(reader io.ReadSeeker, containerSize int64, path []string) (int64, error) {
+	remaining := containerSize
+	for depth := 0; depth < len(path); {
+		atom, headerSize, err := readMp4AtomHeader(reader, remaining)
+		if err != nil {
+			return 0, err
+		}
+		if atom.atomType != path[depth] {
+			if _, err := reader.Seek(atom.payloadSize, io.SeekCurrent); err != nil {
+				return 0, err
+			}
+			remaining -= headerSize + atom.payloadSize
+			continue
+		}
+		remaining = atom.payloadSize
+		if atom.atomType == "meta" {
+			// meta is a full box with version and flags, except in some QuickTime files
+			peek := make([]byte, 8)
+			if _, err := io.ReadFull(reader, peek); err != nil {
+				return 0, err
+			}
+			if string(peek[4:8]) == "hdlr" {
+				if _, err := reader.Seek(-8, io.SeekCurrent); err != nil {
+					return 0, err
+				}
+			} else {
+				if _, err := reader.Seek(-4, io.SeekCurrent); err != nil {
+					return 0, err
+				}
+				remaining -= 4
+			}
+		}
+		depth++
+	}
+	return remaining, nil
+}
+
+func readMp4AtomHeader(reader io.Reader, remaining int64) (mp4Atom, int64, error) {
+	if remaining < 8 {
+		return mp4Atom{}, 0, errInvalidMp4
+	}
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return mp4Atom{}, 0, err
+	}
+	size := int64(binary.BigEndian.Uint32(header[:4]))
+	headerSize := int64(8)
+	switch size {
+	case 0:
+		size = remaining
+	case 1:
+		largeSize := make([]byte, 8)
+		if _, err := io.ReadFull(reader, largeSize); err != nil {
+			return mp4Atom{}, 0, err
+		}
+		size = int64(binary.BigEndian.Uint64(largeSize))
+		headerSize = 16
+	}
+	if size < headerSize || size > remaining {
+		return mp4Atom{}, 0, errInvalidMp4
+	}
+	return mp4Atom{
+		atomType:    string(header[4:8]),
+		payloadSize: size - headerSize,
+	}, headerSize, nil
+}
+
+// A data atom holds a 4 byte type indicator and a 4 byte locale before its value.
+func mp4DataValue(item []byte) []byte {
+	if len(item) < 16 || string(item[4:8]) != "data" {
+		return nil
+	}
+	dataSize := int(binary.BigEndian.Uint32(item[:4]))
+	if dataSize < 16 || dataSize > len(item) {
+		return nil
+	}
+	return item[16:dataSize]
+}
@@ -0,0 +1,76 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const mpegFrameSearchBytes = 64 * 1024
+
+var mpeg1Layer3Bitrates = []int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320}
+var mpeg2Layer3Bitrates = []int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160}
+var mpeg1SampleRates = []int{44100, 48000, 32000}
+
+// mpegDuration reads the first MPEG layer III frame at or after start. VBR
+// encoders write a Xing or Info header into that frame with the frame count;
+// without one the stream is taken to be constant bitrate and the duration
+// estimated from its size.
+func mpegDuration(reader io.ReadSeeker, start int64) int {
+	end, err := reader.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0
+	}
+	if _, err := reader.Seek(start, io.SeekStart); err != nil {
+		return 0
+	}
+	data := make([]byte, mpegFrameSearchBytes)
+	read, _ := io.ReadFull(reader, data)
+	data = data[:read]
+	for offset := 0; offset+4 <= len(data); offset++ {
+		if data[offset] != 0xFF || data[offset+1]&0xE0 != 0xE0 {
+			continue
+		}
+		version := (data[offset+1] >> 3) & 0x03
+		layer := (data[offset+1] >> 1) & 0x03
+		bitrateIndex := int(data[offset+2] >> 4)
+		sampleRateIndex := int((data[offset+2] >> 2) & 0x03)
+		// only layer III (MP3) frames with a known bitrate and sample rate
+		if version == 1 || layer != 1 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+			continue
+		}
+		isMono := data[offset+3]>>6 == 3
+		bitrate := mpeg2Layer3Bitrates[bitrateIndex]
+		sampleRate := mpeg1SampleRates[sampleRateIndex]
+		samplesPerFrame := 576
+		sideInfoSize := 17
+		if isMono {
+			sideInfoSize = 9
+		}
+		switch version {
+		case 3:
+			bitrate = mpeg1Layer3Bitrates[bitrateIndex]
+			samplesPerFrame = 1152
+			sideInfoSize = 32
+			if isMono {
+				sideInfoSize = 17
+			}
+		case 2:
+			sampleRate /= 2
+		case 0:
+			sampleRate /= 4
+		}
+		xingOffset := offset + 4 + sideInfoSize
+		if xingOffset+12 <= len(data) {
+			marker := data[xingOffset : xingOffset+4]
+			flags := binary.BigEndian.Uint32(data[xingOffset+4 : xingOffset+8])
+			if (bytes.Equal(marker, []byte("Xing")) || bytes.Equal(marker, []byte("Info"))) && flags&0x01 != 0 {
+				frames := int64(binary.BigEndian.Uint32(data[xingOffset+8 : xingOffset+12]))
+				return int((frames*int64(samplesPerFrame) + int64(sampleRate)/2) / int64(sampleRate))
+			}
+		}
+		audioBytes := end - start - int64(offset)
+		return int((audioBytes*8 + int64(bitrate)*500) / (int64(bitrate) * 1000))
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package tags
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Tags struct {
+	Artist      string
+	AlbumArtist string
+	Title       string
+	Album       string
+	Date        string
+	TrackNumber int
+	TrackTotal  int
+	DiscNumber  int
+	// Duration is in seconds, and 0 where it cannot be worked out
+	Duration int
+}
+
+var ErrUnsupportedFormat = errors.New("unsupported audio format")
+
+const maximumMetadataBytes = 16 * 1024 * 1024
+
+// Read detects the container from its leading bytes and reads whichever tag
+// format that container carries: ID3 for MP3, Vorbis comments for FLAC and
+// Ogg, and iTunes-style metadata atoms for MP4/M4A.
+func Read(reader io.ReadSeeker) (Tags, error) {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return Tags{}, ErrUnsupportedFormat
+	}
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return Tags{}, err
+	}
+	switch {
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return readId3v2(reader)
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return readFlac(reader)
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return readOgg(reader)
+	case bytes.Equal(header[4:8], []byte("ftyp")):
+		return readMp4(reader)
+	default:
+		return readId3v1(reader)
+	}
+}
+
+func (tags Tags) IsEmpty() bool {
+	return tags.Artist == "" && tags.AlbumArtist == "" && tags.Title == ""
+}
+
+// Year-only and partial dates are returned as-is; full timestamps are cut
+// to their date part.
+func (tags Tags) ReleaseDate() string {
+	date := strings.TrimSpace(tags.Date)
+	if len(date) > len("2006-01-02") {
+		date = date[:len("2006-01-02")]
+	}
+	return date
+}
+
+// Track and disc numbers are commonly stored as "3" or "3/12".
+func parseTrackNumber(value string) (int, int) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	number, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
+	total := 0
+	if len(parts) == 2 {
+		total, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+	}
+	return number, total
+}
@@ -0,0 +1,215 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	flacStreamInfoBlockType    = 0
+	flacVorbisCommentBlockType = 4
+	opusSampleRate             = 48000
+	oggLastPageSearchBytes     = 64 * 1024
+)
+
+var errInvalidVorbisComment = errors.New("invalid Vorbis comment")
+
+// STREAMINFO is always the first block and holds the sample rate and total
+// sample count the duration is worked out from.
+func readFlac(reader io.ReadSeeker) (Tags, error) {
+	if _, err := reader.Seek(4, io.SeekStart); err != nil {
+		return Tags{}, err
+	}
+	blockHeader := make([]byte, 4)
+	duration := 0
+	for {
+		if _, err := io.ReadFull(reader, blockHeader); err != nil {
+			return Tags{}, err
+		}
+		isLast := blockHeader[0]&0x80 != 0
+		blockType := blockHeader[0] & 0x7F
+		blockLength := int(blockHeader[1])<<16 | int(blockHeader[2])<<8 | int(blockHeader[3])
+		switch {
+		case blockType == flacStreamInfoBlockType && blockLength >= 18:
+			block := make([]byte, blockLength)
+			if _, err := io.ReadFull(reader, block); err != nil {
+				return Tags{}, err
+			}
+			sampleRate := int64(block[10])<<12 | int64(block[11])<<4 | int64(block[12])>>4
+			totalSamples := int64(block[13]&0x0F)<<32 | int64(binary.BigEndian.Uint32(block[14:18]))
+			if sampleRate > 0 {
+				duration = int((totalSamples + sampleRate/2) / sampleRate)
+			}
+		case blockType == flacVorbisCommentBlockType:
+			block := make([]byte, blockLength)
+			if _, err := io.ReadFull(reader, block); err != nil {
+				return Tags{}, err
+			}
+			tags, err := parseVorbisComment(block)
+			tags.Duration = duration
+			return tags, err
+		default:
+			if _, err := reader.Seek(int64(blockLength), io.SeekCurrent); err != nil {
+				return Tags{}, err
+			}
+		}
+		if isLast {
+			return Tags{Duration: duration}, nil
+		}
+	}
+}
+
+// The comment header is the second packet of the first logical stream,
+// possibly spanning several pages, and is prefixed with "\x03vorbis" for
+// Vorbis or "OpusTags" for Opus.
+func readOgg(reader io.ReadSeeker) (Tags, error) {
+	pageHeader := make([]byte, 27)
+	packets := make([][]byte, 0, 2)
+	var packet []byte
+	for len(packets) < 2 {
+		if _, err := io.ReadFull(reader, pageHeader); err != nil {
+			return Tags{}, err
+		}
+		if !bytes.HasPrefix(pageHeader, []byte("OggS")) {
+			return Tags{}, errInvalidVorbisComment
+		}
+		segmentTable := make([]byte, pageHeader[26])
+		if _, err := io.ReadFull(reader, segmentTable); err != nil {
+			return Tags{}, err
+		}
+		for _, segmentLength := range segmentTable {
+			segment := make([]byte, segmentLength)
+			if _, err := io.ReadFull(reader, segment); err != nil {
+				return Tags{}, err
+			}
+			packet = append(packet, segment...)
+			if len(packet) > maximumMetadataBytes {
+				return Tags{}, errInvalidVorbisComment
+			}
+			if segmentLength < 255 {
+				packets = append(packets, packet)
+				packet = nil
+				if len(packets) == 2 {
+					break
+				}
+			}
+		}
+	}
+	var tags Tags
+	var err error
+	commentPacket := packets[1]
+	switch {
+	case bytes.HasPrefix(commentPacket, []byte("\x03vorbis")):
+		tags, err = parseVorbisComment(commentPacket[len("\x03vorbis"):])
+	case bytes.HasPrefix(commentPacket, []byte("OpusTags")):
+		tags, err = parseVorbisComment(commentPacket[len("OpusTags"):])
+	default:
+		return Tags{}, ErrUnsupportedFormat
+	}
+	if err == nil {
+		tags.Duration = oggDuration(reader, packets[0])
+	}
+	return tags, err
+}
+
+// The granule position of the last page counts samples since the start of
+// the stream, at the rate given in the identification header (always 48kHz
+// for Opus, less the encoder's pre-skip).
+func oggDuration(reader io.ReadSeeker, identificationPacket []byte) int {
+	var sampleRate, preSkip int64
+	switch {
+	case bytes.HasPrefix(identificationPacket, []byte("\x01vorbis")) && len(identificationPacket) >= 16:
+		sampleRate = int64(binary.LittleEndian.Uint32(identificationPacket[12:16]))
+	case bytes.HasPrefix(identificationPacket, []byte("OpusHead")) && len(identificationPacket) >= 12:
+		sampleRate = opusSampleRate
+		preSkip = int64(binary.LittleEndian.Uint16(identificationPacket[10:12]))
+	}
+	if sampleRate == 0 {
+		return 0
+	}
+	end, err := reader.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0
+	}
+	start := max(end-oggLastPageSearchBytes, 0)
+	if _, err := reader.Seek(start, io.SeekStart); err != nil {
+		return 0
+	}
+	tail := make([]byte, end-start)
+	if _, err := io.ReadFull(reader, tail); err != nil {
+		return 0
+	}
+	lastPage := bytes.LastIndex(tail, []byte("OggS"))
+	if lastPage < 0 || lastPage+14 > len(tail) {
+		return 0
+	}
+	samples := int64(binary.LittleEndian.Uint64(tail[lastPage+6:lastPage+14])) - preSkip
+	if samples <= 0 {
+		return 0
+	}
+	return int((samples + sampleRate/2) / sampleRate)
+}
+
+func parseVorbisComment(data []byte) (Tags, error) {
+	readLength := func() (int, bool) {
+		if len(data) < 4 {
+			return 0, false
+		}
+		length := int(binary.LittleEndian.Uint32(data[:4]))
+		data = data[4:]
+		return length, length <= len(data)
+	}
+	vendorLength, ok := readLength()
+	if !ok {
+		return Tags{}, errInvalidVorbisComment
+	}
+	data = data[vendorLength:]
+	if len(data) < 4 {
+		return Tags{}, errInvalidVorbisComment
+	}
+	count := int(binary.LittleEndian.Uint32(data[:4]))
+	data = data[4:]
+	comments := make(map[string]string)
+	for i := 0; i < count; i++ {
+		length, ok := readLength()
+		if !ok {
+			return Tags{}, errInvalidVorbisComment
+		}
+		key, value, found := strings.Cut(string(data[:length]), "=")
+		data = data[length:]
+		if !found {
+			continue
+		}
+		key = strings.ToUpper(key)
+		// repeated fields, e.g. one ARTIST per performer, are all kept
+		if existing, ok := comments[key]; ok {
+			comments[key] = existing + ", " + value
+		} else {
+			comments[key] = value
+		}
+	}
+	tags := Tags{
+		Artist:      comments["ARTIST"],
+		AlbumArtist: comments["ALBUMARTIST"],
+		Title:       comments["TITLE"],
+		Album:       comments["ALBUM"],
+		Date:        comments["DATE"],
+	}
+	if tags.Date == "" {
+		tags.Date = comments["YEAR"]
+	}
+	tags.TrackNumber, tags.TrackTotal = parseTrackNumber(comments["TRACKNUMBER"])
+	tags.DiscNumber, _ = parseTrackNumber(comments["DISCNUMBER"])
+	if tags.TrackTotal == 0 {
+		for _, key := range []string{"TRACKTOTAL", "TOTALTRACKS"} {
+			if total, _ := parseTrackNumber(comments[key]); total > 0 {
+				tags.TrackTotal = total
+				break
+			}
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,48 @@
+package tracklist
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	featuringRegex     = regexp.MustCompile(`(?i)[(\[]?\s*\b(feat|ft|featuring)\b.*$`)
+	versionSuffixRegex = regexp.MustCompile(`(?i)\s+-\s+.*\b(remaster(ed)?|version|edit|mix|live)\b.*$`)
+)
+
+// Similarity scores how closely two artist or title strings match, from 0 to
+// 1, ignoring case, punctuation, featured artists and version suffixes such as
+// " - 2011 Remaster" that search results add.
+func Similarity(a string, b string) float64 {
+	aTokens := tokens(a)
+	bTokens := tokens(b)
+	if len(aTokens) == 0 || len(bTokens) == 0 {
+		return 0
+	}
+	remaining := make(map[string]int, len(bTokens))
+	for _, token := range bTokens {
+		remaining[token]++
+	}
+	shared := 0
+	for _, token := range aTokens {
+		if remaining[token] > 0 {
+			remaining[token]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(aTokens)+len(bTokens))
+}
+
+func tokens(value string) []string {
+	value = versionSuffixRegex.ReplaceAllString(value, "")
+	value = featuringRegex.ReplaceAllString(value, "")
+	value = strings.ReplaceAll(strings.ToLower(value), "&", " and ")
+	tokens := strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(tokens) > 1 && tokens[0] == "the" {
+		tokens = tokens[1:]
+	}
+	return tokens
+}
@@ -0,0 +1,29 @@
+package tracklist
+
+import "testing"
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want float64
+	}{
+		{"identical", "River", "River", 1},
+		{"case and punctuation", "don't stop", "Don't Stop!", 1},
+		{"leading the", "The Tragically Hip", "Tragically Hip", 1},
+		{"ampersand", "Simon & Garfunkel", "Simon and Garfunkel", 1},
+		{"featured artist", "Mushaboom", "Mushaboom (feat. Someone)", 1},
+		{"version suffix", "Heroes", "Heroes - 2017 Remaster", 1},
+		{"partial", "Big Yellow Taxi", "Big Taxi", 0.8},
+		{"unrelated", "River", "Mushaboom", 0},
+		{"empty", "", "River", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Similarity(test.a, test.b); got != test.want {
+				t.Errorf("Similarity(%q, %q): got %v, want %v", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
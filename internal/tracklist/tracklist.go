@@ -0,0 +1,133 @@
+package tracklist
+
+import (
+	"regexp"
+	"strings"
+)
+
+type Line struct {
+	Artist string
+	Title  string
+	Album  string
+	// Confidence is how sure the parser is that Artist and Title were
+	// separated correctly, from 0 (no separator found) to 1.
+	Confidence float64
+}
+
+const (
+	separatedConfidence   = 1
+	byConfidence          = 0.8
+	unseparatedConfidence = 0
+)
+
+// Dashes are tried before "by", which also occurs inside titles. En and em
+// dashes are rarely part of a name, so they also count without spaces.
+var dashSeparators = []string{" – ", " — ", " - ", "–", "—"}
+
+var (
+	timestampPrefixRegex  = regexp.MustCompile(`^\[?\d{1,2}:\d{2}(?::\d{2})?\]?\s*`)
+	numberingPrefixRegex  = regexp.MustCompile(`^(?:#\s*\d{1,3}|\d{1,3}\s*[.):\]])\s*(?:-\s+)?`)
+	bareNumberPrefixRegex = regexp.MustCompile(`^\d{1,3}\s+(?:-\s+)?`)
+	byRegex               = regexp.MustCompile(`(?i)\s+by\s+`)
+	trailingGroupRegex    = regexp.MustCompile(`^(.*\S)\s*[(\[]([^()\[\]]+)[)\]]$`)
+	versionRegex          = regexp.MustCompile(`(?i)\b(feat|ft|featuring|remix|mix|edit|version|live|remaster(ed)?|demo|acoustic|instrumental|extended|dub|cover|mono|stereo|bonus)\b`)
+)
+
+// Parse reads one track per line from free text such as a host's notes, e.g.
+// "1. Artist – Title (Album)" or "Title by Artist". Blank lines are skipped.
+func Parse(text string) []Line {
+	rawLines := make([]string, 0)
+	for _, rawLine := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		rawLine = strings.TrimSpace(timestampPrefixRegex.ReplaceAllString(strings.TrimSpace(rawLine), ""))
+		if rawLine != "" {
+			rawLines = append(rawLines, rawLine)
+		}
+	}
+	// "99 Problems" starts with a number too, so bare numbers are only
+	// treated as numbering when every line has one
+	stripBareNumbers := len(rawLines) > 1
+	for _, rawLine := range rawLines {
+		if !numberingPrefixRegex.MatchString(rawLine) && !bareNumberPrefixRegex.MatchString(rawLine) {
+			stripBareNumbers = false
+			break
+		}
+	}
+	lines := make([]Line, 0, len(rawLines))
+	for _, rawLine := range rawLines {
+		rawLine = numberingPrefixRegex.ReplaceAllString(rawLine, "")
+		if stripBareNumbers {
+			rawLine = bareNumberPrefixRegex.ReplaceAllString(rawLine, "")
+		}
+		if rawLine == "" {
+			continue
+		}
+		lines = append(lines, parseLine(rawLine))
+	}
+	return lines
+}
+
+func parseLine(rawLine string) Line {
+	if fields := strings.Split(rawLine, "\t"); len(fields) > 1 {
+		line := Line{
+			Artist:     cleanField(fields[0]),
+			Title:      cleanField(fields[1]),
+			Confidence: separatedConfidence,
+		}
+		if len(fields) > 2 {
+			line.Album = cleanField(fields[2])
+		} else {
+			line.Title, line.Album = splitAlbum(line.Title)
+		}
+		return line
+	}
+	for _, separator := range dashSeparators {
+		if artist, title, found := strings.Cut(rawLine, separator); found && strings.TrimSpace(artist) != "" && strings.TrimSpace(title) != "" {
+			title, album := splitAlbum(cleanField(title))
+			return Line{
+				Artist:     cleanField(artist),
+				Title:      title,
+				Album:      album,
+				Confidence: separatedConfidence,
+			}
+		}
+	}
+	// the last "by" is the separator, e.g. "Stand by Me by Ben E. King"
+	if matches := byRegex.FindAllStringIndex(rawLine, -1); len(matches) > 0 {
+		match := matches[len(matches)-1]
+		if match[0] > 0 && match[1] < len(rawLine) {
+			artist, album := splitAlbum(cleanField(rawLine[match[1]:]))
+			return Line{
+				Artist:     artist,
+				Title:      cleanField(rawLine[:match[0]]),
+				Album:      album,
+				Confidence: byConfidence,
+			}
+		}
+	}
+	return Line{
+		Title:      cleanField(rawLine),
+		Confidence: unseparatedConfidence,
+	}
+}
+
+// A trailing parenthesised or bracketed group is the album, unless it
+// describes the version of the track, e.g. "(feat. X)" or "[Live]".
+func splitAlbum(value string) (string, string) {
+	matches := trailingGroupRegex.FindStringSubmatch(value)
+	if matches == nil || versionRegex.MatchString(matches[2]) {
+		return value, ""
+	}
+	return cleanField(matches[1]), cleanField(matches[2])
+}
+
+func cleanField(value string) string {
+	value = strings.TrimSpace(value)
+	for _, quotes := range []string{`""`, "“”", "''", "‘’"} {
+		opening, closing := []rune(quotes)[0], []rune(quotes)[1]
+		runes := []rune(value)
+		if len(runes) > 1 && runes[0] == opening && runes[len(runes)-1] == closing {
+			return strings.TrimSpace(string(runes[1 : len(runes)-1]))
+		}
+	}
+	return value
+}
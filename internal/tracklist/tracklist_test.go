@@ -0,0 +1,107 @@
+package tracklist
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Line
+	}{
+		{
+			name: "hyphen",
+			text: "Joni Mitchell - River",
+			want: []Line{{Artist: "Joni Mitchell", Title: "River", Confidence: separatedConfidence}},
+		},
+		{
+			name: "en dash without spaces",
+			text: "Joni Mitchell–River",
+			want: []Line{{Artist: "Joni Mitchell", Title: "River", Confidence: separatedConfidence}},
+		},
+		{
+			name: "em dash",
+			text: "Joni Mitchell — River",
+			want: []Line{{Artist: "Joni Mitchell", Title: "River", Confidence: separatedConfidence}},
+		},
+		{
+			name: "tab separated with album",
+			text: "Joni Mitchell\tRiver\tBlue",
+			want: []Line{{Artist: "Joni Mitchell", Title: "River", Album: "Blue", Confidence: separatedConfidence}},
+		},
+		{
+			name: "album in parentheses",
+			text: "Joni Mitchell - River (Blue)",
+			want: []Line{{Artist: "Joni Mitchell", Title: "River", Album: "Blue", Confidence: separatedConfidence}},
+		},
+		{
+			name: "version in brackets is not an album",
+			text: "Joni Mitchell - River [Live]",
+			want: []Line{{Artist: "Joni Mitchell", Title: "River [Live]", Confidence: separatedConfidence}},
+		},
+		{
+			name: "quoted title",
+			text: "Joni Mitchell - “River”",
+			want: []Line{{Artist: "Joni Mitchell", Title: "River", Confidence: separatedConfidence}},
+		},
+		{
+			name: "by",
+			text: "River by Joni Mitchell",
+			want: []Line{{Artist: "Joni Mitchell", Title: "River", Confidence: byConfidence}},
+		},
+		{
+			name: "last by",
+			text: "Stand by Me by Ben E. King",
+			want: []Line{{Artist: "Ben E. King", Title: "Stand by Me", Confidence: byConfidence}},
+		},
+		{
+			name: "dash before by",
+			text: "Ben E. King - Stand by Me",
+			want: []Line{{Artist: "Ben E. King", Title: "Stand by Me", Confidence: separatedConfidence}},
+		},
+		{
+			name: "no separator",
+			text: "River",
+			want: []Line{{Title: "River", Confidence: unseparatedConfidence}},
+		},
+		{
+			name: "timestamps and numbering",
+			text: "[00:00] 1. Joni Mitchell - River\r\n\r\n[04:01] 2) Feist - Mushaboom\n",
+			want: []Line{
+				{Artist: "Joni Mitchell", Title: "River", Confidence: separatedConfidence},
+				{Artist: "Feist", Title: "Mushaboom", Confidence: separatedConfidence},
+			},
+		},
+		{
+			name: "bare numbers on every line",
+			text: "01 Joni Mitchell - River\n02 Feist - Mushaboom",
+			want: []Line{
+				{Artist: "Joni Mitchell", Title: "River", Confidence: separatedConfidence},
+				{Artist: "Feist", Title: "Mushaboom", Confidence: separatedConfidence},
+			},
+		},
+		{
+			name: "bare number in a title",
+			text: "Jay-Z - 99 Problems\n99 Problems by Jay-Z",
+			want: []Line{
+				{Artist: "Jay-Z", Title: "99 Problems", Confidence: separatedConfidence},
+				{Artist: "Jay-Z", Title: "99 Problems", Confidence: byConfidence},
+			},
+		},
+		{
+			name: "blank",
+			text: " \n\n",
+			want: []Line{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Parse(test.text)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Parse:\n got %+v\nwant %+v", got, test.want)
+			}
+		})
+	}
+}
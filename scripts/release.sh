@@ -13,9 +13,11 @@ local_output_dir=$(pwd)/dist/$date_tag
 mkdir -p $local_output_dir
 
 cp bookmarklet.js $local_output_dir/
-if [ -f cmd/cick-playlister/credentials.json ]; then
-    cp cmd/cick-playlister/credentials.json $local_output_dir/
-fi
+for config_file in credentials.json automation-logs.json canadian-artists.json instrumentals.json inserts.json new-releases.json local-files.json bandcamp-domains.json; do
+    if [ -f cmd/cick-playlister/$config_file ]; then
+        cp cmd/cick-playlister/$config_file $local_output_dir/
+    fi
+done
 
 image_name="captaincoordinates/cick-playlister-builder"
 